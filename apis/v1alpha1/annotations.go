@@ -21,4 +21,9 @@ const (
 	// of days the "PendingWindowInDays" value should assume when deleting a
 	// Key.
 	AnnotationDeletePendingWindow = AnnotationPrefix + "pending-window-in-days"
+	// AnnotationPolicyRefsRevision is an annotation the controller sets on a
+	// Key whose value is the list of the resource versions of the ConfigMaps
+	// it references in Spec.PolicyRefs, so that the Key is updated whenever
+	// one of its key policy fragments changes.
+	AnnotationPolicyRefsRevision = AnnotationPrefix + "policy-refs-revision"
 )
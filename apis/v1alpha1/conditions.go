@@ -0,0 +1,35 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+const (
+	// ConditionTypeKeyUsable indicates whether the KMS key can be used in
+	// cryptographic operations. When the condition is False, its reason is
+	// the KeyState of the KMS key and its message explains what the key is
+	// waiting for.
+	ConditionTypeKeyUsable ackv1alpha1.ConditionType = "KMS.KeyUsable"
+	// ConditionTypeKeyMaterialExpiring is a warning that the imported key
+	// material of the KMS key expires soon. Its reason is the smallest
+	// threshold of Spec.KeyMaterialExpiryWarningDays reached, e.g.
+	// ExpiresWithin7Days.
+	ConditionTypeKeyMaterialExpiring ackv1alpha1.ConditionType = "KMS.KeyMaterialExpiring"
+	// ConditionTypePolicyDrifted indicates that the key policy of the KMS key
+	// differs from the desired key policy of a Key whose Spec.PolicyDriftMode
+	// is observe. Its message summarizes the statements that differ.
+	ConditionTypePolicyDrifted ackv1alpha1.ConditionType = "KMS.PolicyDrifted"
+)
@@ -21,6 +21,21 @@ import (
 )
 
 // KeySpec defines the desired state of Key.
+// +kubebuilder:validation:XValidation:rule="!has(self.xksKeyID) || has(self.customKeyStoreID) || has(self.customKeyStoreRef)",message="xksKeyID can only be set along with customKeyStoreID or customKeyStoreRef"
+// +kubebuilder:validation:XValidation:rule="!has(self.keyUsage) || self.keyUsage != 'KEY_AGREEMENT' || (has(self.keySpec) && self.keySpec in ['ECC_NIST_P256', 'ECC_NIST_P384', 'ECC_NIST_P521', 'SM2'])",message="keyUsage KEY_AGREEMENT requires a keySpec of ECC_NIST_P256, ECC_NIST_P384, ECC_NIST_P521 or SM2"
+// +kubebuilder:validation:XValidation:rule="has(self.xksKeyID) == (has(self.origin) && self.origin == 'EXTERNAL_KEY_STORE')",message="xksKeyID is required with, and only valid with, origin EXTERNAL_KEY_STORE"
+// +kubebuilder:validation:XValidation:rule="!has(self.origin) || !(self.origin in ['AWS_CLOUDHSM', 'EXTERNAL_KEY_STORE']) || has(self.customKeyStoreID) || has(self.customKeyStoreRef)",message="origin AWS_CLOUDHSM and EXTERNAL_KEY_STORE require customKeyStoreID or customKeyStoreRef"
+// +kubebuilder:validation:XValidation:rule="!(has(self.customKeyStoreID) || has(self.customKeyStoreRef)) || (has(self.origin) && self.origin in ['AWS_CLOUDHSM', 'EXTERNAL_KEY_STORE'])",message="keys in a custom key store require origin AWS_CLOUDHSM or EXTERNAL_KEY_STORE"
+// +kubebuilder:validation:XValidation:rule="!(has(self.customKeyStoreID) || has(self.customKeyStoreRef)) || !has(self.keySpec) || self.keySpec == 'SYMMETRIC_DEFAULT'",message="keys in a custom key store require keySpec SYMMETRIC_DEFAULT"
+// +kubebuilder:validation:XValidation:rule="!(has(self.customKeyStoreID) || has(self.customKeyStoreRef)) || !has(self.multiRegion) || !self.multiRegion",message="multiRegion keys cannot be created in a custom key store"
+// +kubebuilder:validation:XValidation:rule="(has(self.keySpec) && self.keySpec != 'SYMMETRIC_DEFAULT') || !has(self.keyUsage) || self.keyUsage == 'ENCRYPT_DECRYPT'",message="keySpec SYMMETRIC_DEFAULT only supports keyUsage ENCRYPT_DECRYPT"
+// +kubebuilder:validation:XValidation:rule="!has(self.keySpec) || !self.keySpec.startsWith('HMAC_') || (has(self.keyUsage) && self.keyUsage == 'GENERATE_VERIFY_MAC')",message="HMAC keySpecs require keyUsage GENERATE_VERIFY_MAC"
+// +kubebuilder:validation:XValidation:rule="!has(self.keySpec) || !self.keySpec.startsWith('RSA_') || !has(self.keyUsage) || self.keyUsage in ['ENCRYPT_DECRYPT', 'SIGN_VERIFY']",message="RSA keySpecs only support keyUsage ENCRYPT_DECRYPT or SIGN_VERIFY"
+// +kubebuilder:validation:XValidation:rule="!has(self.keySpec) || !self.keySpec.startsWith('ECC_NIST_') || (has(self.keyUsage) && self.keyUsage in ['SIGN_VERIFY', 'KEY_AGREEMENT'])",message="ECC_NIST keySpecs require keyUsage SIGN_VERIFY or KEY_AGREEMENT"
+// +kubebuilder:validation:XValidation:rule="!has(self.keySpec) || self.keySpec != 'ECC_SECG_P256K1' || (has(self.keyUsage) && self.keyUsage == 'SIGN_VERIFY')",message="keySpec ECC_SECG_P256K1 requires keyUsage SIGN_VERIFY"
+// +kubebuilder:validation:XValidation:rule="!has(self.keySpec) || self.keySpec != 'SM2' || !has(self.keyUsage) || self.keyUsage != 'GENERATE_VERIFY_MAC'",message="keySpec SM2 does not support keyUsage GENERATE_VERIFY_MAC"
+// +kubebuilder:validation:XValidation:rule="!(has(self.policy) && has(self.policyDocument))",message="policy and policyDocument are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.policyRefs) && has(self.policyDriftMode) && self.policyDriftMode == 'adopt')",message="policyDriftMode adopt cannot be used with policyRefs"
 type KeySpec struct {
 
 	// Skips ("bypasses") the key policy lockout safety check. The default value
@@ -35,6 +50,9 @@ type KeySpec struct {
 	// Use this parameter only when you intend to prevent the principal that is
 	// making the request from making a subsequent PutKeyPolicy (https://docs.aws.amazon.com/kms/latest/APIReference/API_PutKeyPolicy.html)
 	// request on the KMS key.
+	//
+	// Unless this value is true, the controller refuses key policies that do
+	// not allow its own identity to call PutKeyPolicy on the KMS key.
 	BypassPolicyLockoutSafetyCheck *bool `json:"bypassPolicyLockoutSafetyCheck,omitempty"`
 	// Creates the KMS key in the specified custom key store (https://docs.aws.amazon.com/kms/latest/developerguide/custom-key-store-overview.html).
 	// The ConnectionState of the custom key store must be CONNECTED. To find the
@@ -49,7 +67,13 @@ type KeySpec struct {
 	// must use the XksKeyId parameter to specify an external key that serves as
 	// key material for the KMS key.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
-	CustomKeyStoreID *string `json:"customKeyStoreID,omitempty"`
+	CustomKeyStoreID  *string                                  `json:"customKeyStoreID,omitempty"`
+	CustomKeyStoreRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"customKeyStoreRef,omitempty"`
+	// Prevents the KMS key from being scheduled for deletion when the Key resource
+	// is deleted. While deletion protection is enabled, deleting the Key resource
+	// leaves the KMS key untouched and sets an ACK.Terminal condition on the
+	// resource. Set this field to false to let the pending deletion proceed.
+	DeletionProtectionEnabled *bool `json:"deletionProtectionEnabled,omitempty"`
 	// A description of the KMS key. Use a description that helps you decide whether
 	// the KMS key is appropriate for a task. The default value is an empty string
 	// (no description).
@@ -58,9 +82,57 @@ type KeySpec struct {
 	// field may be displayed in plaintext in CloudTrail logs and other output.
 	//
 	// To set or change the description after the key is created, use UpdateKeyDescription.
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	Description       *string `json:"description,omitempty"`
 	EnableKeyRotation *bool   `json:"enableKeyRotation,omitempty"`
+	Enabled           *bool   `json:"enabled,omitempty"`
+	// A reference to the Secret key that holds the key material to import into
+	// a KMS key whose Origin is EXTERNAL, encrypted with the public key in
+	// Status.PublicKey using WrappingAlgorithm. The key material is imported with
+	// the import token in Status.ImportToken, which comes from the same
+	// GetParametersForImport call as the public key.
+	//
+	// While the KMS key is PendingImport, the controller also writes the public
+	// key and the import token to the publicKey and importToken keys of the same
+	// Secret, which must exist. They can be published to a ConfigMap with a FieldExport
+	// resource as well. The key material is imported as soon as it is present in
+	// the Secret.
+	EncryptedKeyMaterial *ackv1alpha1.SecretKeyReference `json:"encryptedKeyMaterial,omitempty"`
+	// Specifies whether the imported key material expires. The default is KEY_MATERIAL_EXPIRES.
+	// For help with this choice, see Setting an expiration time (https://docs.aws.amazon.com/en_us/kms/latest/developerguide/importing-keys.html#importing-keys-expiration)
+	// in the Key Management Service Developer Guide.
+	//
+	// When the value of ExpirationModel is KEY_MATERIAL_EXPIRES, you must specify
+	// a value for the ValidTo parameter. When value is KEY_MATERIAL_DOES_NOT_EXPIRE,
+	// you must omit the ValidTo parameter.
+	//
+	// You cannot change the ExpirationModel or ValidTo values for the current import
+	// after the request completes. To change either value, you must reimport the
+	// key material.
+	ImportExpirationModel *string `json:"importExpirationModel,omitempty"`
+	// The date and time when the imported key material expires. This parameter
+	// is required when the value of the ExpirationModel parameter is KEY_MATERIAL_EXPIRES.
+	// Otherwise it is not valid.
+	//
+	// The value of this parameter must be a future date and time. The maximum value
+	// is 365 days from the request date.
+	//
+	// When the key material expires, KMS deletes the key material from the KMS
+	// key. Without its key material, the KMS key is unusable. To use the KMS key
+	// in cryptographic operations, you must reimport the same key material.
+	ImportValidTo *metav1.Time `json:"importValidTo,omitempty"`
+	// The number of days the imported key material is valid for, counted from
+	// each import. When set, it takes precedence over ImportValidTo and lets the
+	// controller re-import the key material before it expires, see
+	// ReimportKeyMaterialBeforeExpiryInDays.
+	//
+	// The value must be between 1 and 365, inclusive.
+	// +kubebuilder:validation:XValidation:rule="self >= 1 && self <= 365",message="Value must be between 1 and 365"
+	ImportValidityInDays *int64 `json:"importValidityInDays,omitempty"`
+	// The number of days before the imported key material expires at which the
+	// KMS.KeyMaterialExpiring condition is raised. The reason of the condition
+	// is the smallest threshold reached. If no value is specified, the thresholds
+	// are 30, 7 and 1 days.
+	KeyMaterialExpiryWarningDays []*int64 `json:"keyMaterialExpiryWarningDays,omitempty"`
 	// Specifies the type of KMS key to create. The default value, SYMMETRIC_DEFAULT,
 	// creates a KMS key with a 256-bit AES-GCM key that is used for encryption
 	// and decryption, except in China Regions, where it creates a 128-bit symmetric
@@ -151,7 +223,8 @@ type KeySpec struct {
 	// a multi-Region key with imported key material. However, you cannot create
 	// a multi-Region key in a custom key store.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
-	MultiRegion *bool `json:"multiRegion,omitempty"`
+	MultiRegion                *bool  `json:"multiRegion,omitempty"`
+	OnDemandRotationGeneration *int64 `json:"onDemandRotationGeneration,omitempty"`
 	// The source of the key material for the KMS key. You cannot change the origin
 	// after you create the KMS key. The default is AWS_KMS, which means that KMS
 	// creates the key material.
@@ -173,6 +246,16 @@ type KeySpec struct {
 	// identify the associated external key. The KeySpec value must be SYMMETRIC_DEFAULT.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	Origin *string `json:"origin,omitempty"`
+	// The waiting period, specified in number of days, before KMS deletes the
+	// KMS key once the Key resource is deleted. During the waiting period the
+	// KMS key is in the PendingDeletion state, and re-creating or adopting the
+	// Key resource cancels its deletion.
+	//
+	// The value must be between 7 and 30, inclusive. If no value is specified,
+	// the default value is 7 days. The kms.services.k8s.aws/pending-window-in-days
+	// annotation, when set to a valid value, takes precedence over this field.
+	// +kubebuilder:validation:XValidation:rule="self >= 7 && self <= 30",message="Value must be between 7 and 30"
+	PendingWindowInDays *int64 `json:"pendingWindowInDays,omitempty"`
 	// The key policy to attach to the KMS key.
 	//
 	// If you provide a key policy, it must meet the following criteria:
@@ -204,6 +287,67 @@ type KeySpec struct {
 	//
 	// Regex Pattern: `^[\u0009\u000A\u000D\u0020-\u00FF]+$`
 	Policy *string `json:"policy,omitempty"`
+	// The key policy to attach to the KMS key, authored as structured statements
+	// instead of a JSON document. The controller renders it to JSON before it
+	// calls CreateKey or PutKeyPolicy. PolicyDocument and Policy are mutually
+	// exclusive.
+	PolicyDocument *KeyPolicyDocument `json:"policyDocument,omitempty"`
+	// How the controller handles differences between the key policy of the KMS
+	// key and the desired key policy, for example after the key policy is edited
+	// outside of the controller. In enforce mode, the default, the key policy is
+	// updated to the desired one. In observe mode, the key policy is left as is once
+	// the key is created and differences are reported in the KMS.PolicyDrifted
+	// condition. In adopt mode, the key policy of the KMS key is written back to
+	// Policy, or PolicyDocument, so that it becomes the desired key policy. The adopt
+	// mode cannot be used with PolicyRefs.
+	// +kubebuilder:validation:Enum=enforce;observe;adopt
+	PolicyDriftMode *string `json:"policyDriftMode,omitempty"`
+	// ConfigMaps in the namespace of the Key holding key policy fragments to merge
+	// into the key policy. Each fragment is a JSON key policy document, or a JSON
+	// list of statements, whose statements are added to those of Policy or PolicyDocument.
+	// Statements whose Sid is already in the key policy are skipped.
+	//
+	// Fragments may use the ${AccountId}, ${Region}, ${Partition} and ${KeyArn}
+	// placeholders, which are replaced with the values of the key. Fragments using
+	// ${KeyArn} are merged once the key is created. The key policy is updated
+	// when a referenced ConfigMap changes.
+	PolicyRefs []*KeyPolicyFragmentReference `json:"policyRefs,omitempty"`
+	// The Amazon Web Services Region of the primary key of a multi-Region key.
+	// Enter the Region ID, such as us-east-1 or ap-southeast-2. There must be an
+	// existing replica key in this Region.
+	//
+	// When this value differs from the Region of the current primary key, the
+	// replica key in this Region is promoted to primary key with UpdatePrimaryRegion
+	// and the current primary key becomes a replica key. This field is valid only
+	// for multi-Region keys.
+	PrimaryRegion *string `json:"primaryRegion,omitempty"`
+	// The number of days before the imported key material expires at which the
+	// controller re-imports it. New import parameters are written to the Secret
+	// referenced by EncryptedKeyMaterial, and the key material found in that
+	// Secret once it is wrapped with the new public key is imported. The new
+	// expiration date is set from ImportValidityInDays or, when it is later than
+	// the current one, from ImportValidTo.
+	//
+	// If no value is specified, the key material is not re-imported.
+	// +kubebuilder:validation:XValidation:rule="self >= 1",message="Value must be greater than or equal to 1"
+	ReimportKeyMaterialBeforeExpiryInDays *int64 `json:"reimportKeyMaterialBeforeExpiryInDays,omitempty"`
+	// Whether the default key policy is restored once Policy, PolicyDocument and
+	// PolicyRefs are all removed from the Key. The default key policy gives the
+	// account of the KMS key full access to it, which lets IAM policies control
+	// access to the key. The default value is false, which leaves the key policy
+	// of the KMS key as is when the Key does not specify one.
+	RestoreDefaultPolicy *bool `json:"restoreDefaultPolicy,omitempty"`
+	// Use this parameter to specify a custom period of time between each rotation
+	// date. If no value is specified, the default value is 365 days.
+	//
+	// The rotation period defines the number of days after you enable automatic
+	// key rotation that KMS will rotate your key material, and the number of days
+	// between each automatic rotation thereafter.
+	//
+	// You can use the kms:RotationPeriodInDays (https://docs.aws.amazon.com/kms/latest/developerguide/conditions-kms.html#conditions-kms-rotation-period-in-days)
+	// condition key to further constrain the values that principals can specify
+	// in the RotationPeriodInDays parameter.
+	RotationPeriodInDays *int64 `json:"rotationPeriodInDays,omitempty"`
 	// Assigns one or more tags to the KMS key. Use this parameter to tag the KMS
 	// key when it is created. To tag an existing KMS key, use the TagResource operation.
 	//
@@ -228,6 +372,42 @@ type KeySpec struct {
 	// Tags can also be used to control access to a KMS key. For details, see Tagging
 	// Keys (https://docs.aws.amazon.com/kms/latest/developerguide/tagging-keys.html).
 	Tags []*Tag `json:"tags,omitempty"`
+	// The algorithm you will use with the RSA public key (PublicKey) in the response
+	// to protect your key material during import. The default is RSAES_OAEP_SHA_256.
+	// For more information, see Select a wrapping algorithm (https://docs.aws.amazon.com/kms/latest/developerguide/importing-keys-get-public-key-and-token.html#select-wrapping-algorithm)
+	// in the Key Management Service Developer Guide.
+	WrappingAlgorithm *string `json:"wrappingAlgorithm,omitempty"`
+	// The type of RSA public key to return in the response. You will use this
+	// wrapping key with the specified wrapping algorithm to protect your key material
+	// during import. The default is RSA_4096.
+	WrappingKeySpec *string `json:"wrappingKeySpec,omitempty"`
+	// Identifies the external key (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html#concept-external-key)
+	// that serves as key material for the KMS key in an external key store (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html).
+	// Specify the ID that the external key store proxy (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html#concept-xks-proxy)
+	// uses to refer to the external key. For help, see the documentation for your
+	// external key store proxy.
+	//
+	// This parameter is required for a KMS key with an Origin value of EXTERNAL_KEY_STORE.
+	// It is not valid for KMS keys with any other Origin value.
+	//
+	// The external key must be an existing 256-bit AES symmetric encryption key
+	// hosted outside of Amazon Web Services in an external key manager associated
+	// with the external key store specified by the CustomKeyStoreId parameter.
+	// This key must be enabled and configured to perform encryption and decryption.
+	// Each KMS key in an external key store must use a different external key.
+	// For details, see Requirements for a KMS key in an external key store (https://docs.aws.amazon.com/kms/latest/developerguide/create-xks-keys.html#xks-key-requirements)
+	// in the Key Management Service Developer Guide.
+	//
+	// Each KMS key in an external key store is associated two backing keys. One
+	// is key material that KMS generates. The other is the external key specified
+	// by this parameter. When you use the KMS key in an external key store to encrypt
+	// data, the encryption operation is performed first by KMS using the KMS key
+	// material, and then by the external key manager using the specified external
+	// key, a process known as double encryption. For details, see Double encryption
+	// (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html#concept-double-encryption)
+	// in the Key Management Service Developer Guide.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	XksKeyID *string `json:"xksKeyID,omitempty"`
 }
 
 // KeyStatus defines the observed state of Key
@@ -282,6 +462,21 @@ type KeyStatus struct {
 	// only when Origin is EXTERNAL, otherwise this value is omitted.
 	// +kubebuilder:validation:Optional
 	ExpirationModel *string `json:"expirationModel,omitempty"`
+	// The time at which the import token and public key in Status.ImportToken
+	// and Status.PublicKey expire. After this time, new parameters are requested
+	// from KMS and key material wrapped with the previous public key cannot be
+	// imported anymore.
+	// +kubebuilder:validation:Optional
+	ImportParametersValidTo *metav1.Time `json:"importParametersValidTo,omitempty"`
+	// The import token to send in a subsequent ImportKeyMaterial request. Only
+	// present while the KMS key is PendingImport.
+	// +kubebuilder:validation:Optional
+	ImportToken []byte `json:"importToken,omitempty"`
+	// The key agreement algorithm used to derive a shared secret.
+	//
+	// This value is present only when the KeyUsage of the KMS key is KEY_AGREEMENT.
+	// +kubebuilder:validation:Optional
+	KeyAgreementAlgorithms []*string `json:"keyAgreementAlgorithms,omitempty"`
 	// The globally unique identifier for the KMS key.
 	// +kubebuilder:validation:Optional
 	KeyID *string `json:"keyID,omitempty"`
@@ -298,6 +493,8 @@ type KeyStatus struct {
 	// in the Key Management Service Developer Guide.
 	// +kubebuilder:validation:Optional
 	KeyState *string `json:"keyState,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastOnDemandRotationGeneration *int64 `json:"lastOnDemandRotationGeneration,omitempty"`
 	// The message authentication code (MAC) algorithm that the HMAC KMS key supports.
 	//
 	// This value is present only when the KeyUsage of the KMS key is GENERATE_VERIFY_MAC.
@@ -318,6 +515,18 @@ type KeyStatus struct {
 	//    field includes the current KMS key if it is a replica key.
 	// +kubebuilder:validation:Optional
 	MultiRegionConfiguration *MultiRegionConfiguration `json:"multiRegionConfiguration,omitempty"`
+	// The next date that KMS will automatically rotate the key material.
+	// +kubebuilder:validation:Optional
+	NextRotationDate *metav1.Time `json:"nextRotationDate,omitempty"`
+	// Identifies the date and time that an in progress on-demand rotation was initiated.
+	//
+	// The KMS API follows an eventual consistency (https://docs.aws.amazon.com/kms/latest/developerguide/programming-eventual-consistency.html)
+	// model due to the distributed nature of the system. As a result, there might
+	// be a slight delay between initiating on-demand key rotation and the rotation's
+	// completion. Once the on-demand rotation is complete, use ListKeyRotations
+	// to view the details of the on-demand rotation.
+	// +kubebuilder:validation:Optional
+	OnDemandRotationStartDate *metav1.Time `json:"onDemandRotationStartDate,omitempty"`
 	// The waiting period before the primary key in a multi-Region key is deleted.
 	// This waiting period begins when the last of its replica keys is deleted.
 	// This value is present only when the KeyState of the KMS key is PendingReplicaDeletion.
@@ -333,6 +542,22 @@ type KeyStatus struct {
 	// to PendingDeletion and the deletion date appears in the DeletionDate field.
 	// +kubebuilder:validation:Optional
 	PendingDeletionWindowInDays *int64 `json:"pendingDeletionWindowInDays,omitempty"`
+	// +kubebuilder:validation:Optional
+	PolicyApplied *bool `json:"policyApplied,omitempty"`
+	// A list of key policy names.
+	//
+	// The only valid value is default. The controller only manages the default
+	// key policy, other key policies are reported in an ACK.Advisory condition.
+	// +kubebuilder:validation:Optional
+	PolicyNames []*string `json:"policyNames,omitempty"`
+	// The public key to use to encrypt the key material before importing it with
+	// ImportKeyMaterial. Only present while the KMS key is PendingImport.
+	// +kubebuilder:validation:Optional
+	PublicKey []byte `json:"publicKey,omitempty"`
+	// The most recent completed rotations of the key material, newest first,
+	// as reported by ListKeyRotations. At most 10 rotations are listed.
+	// +kubebuilder:validation:Optional
+	RecentRotations []*RotationsListEntry `json:"recentRotations,omitempty"`
 	// The signing algorithms that the KMS key supports. You cannot use the KMS
 	// key with other signing algorithms within KMS.
 	//
@@ -345,6 +570,13 @@ type KeyStatus struct {
 	// is KEY_MATERIAL_EXPIRES, otherwise this value is omitted.
 	// +kubebuilder:validation:Optional
 	ValidTo *metav1.Time `json:"validTo,omitempty"`
+	// Information about the external key that is associated with a KMS key in an
+	// external key store.
+	//
+	// For more information, see External key (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html#concept-external-key)
+	// in the Key Management Service Developer Guide.
+	// +kubebuilder:validation:Optional
+	XksKeyConfiguration *XksKeyConfigurationType `json:"xksKeyConfiguration,omitempty"`
 }
 
 // Key is the Schema for the Keys API
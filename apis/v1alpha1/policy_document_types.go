@@ -0,0 +1,80 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// The types below are referenced by fields of the Key resource in
+// generator.yaml. They have no equivalent in the KMS API, so they are not
+// generated.
+
+// A condition of a key policy statement. The statement is in effect only when
+// the condition operator matches the condition key against one of the values.
+// For more information, see Condition keys for KMS (https://docs.aws.amazon.com/kms/latest/developerguide/policy-conditions.html)
+// in the Key Management Service Developer Guide.
+type KeyPolicyCondition struct {
+	// The condition key, such as kms:CallerAccount or aws:PrincipalArn.
+	// +kubebuilder:validation:Required
+	Key *string `json:"key"`
+	// The condition operator, such as StringEquals or ArnLike.
+	// +kubebuilder:validation:Required
+	Operator *string `json:"operator"`
+	// +kubebuilder:validation:Required
+	Values []*string `json:"values"`
+}
+
+// A key policy authored as structured statements. The controller renders it
+// to the JSON key policy document that KMS expects.
+type KeyPolicyDocument struct {
+	ID *string `json:"id,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Statements []*KeyPolicyStatement `json:"statements"`
+	// The version of the policy language. The default value is 2012-10-17.
+	Version *string `json:"version,omitempty"`
+}
+
+// A reference to a key policy fragment held in a ConfigMap.
+type KeyPolicyFragmentReference struct {
+	// The key of the ConfigMap entry holding the key policy fragment. The default
+	// value is policy.
+	Key *string `json:"key,omitempty"`
+	// The name of the ConfigMap.
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
+}
+
+// The principals a key policy statement applies to. Use "*" as an AWS
+// principal to apply the statement to all principals.
+type KeyPolicyPrincipals struct {
+	AWS       []*string `json:"aws,omitempty"`
+	Federated []*string `json:"federated,omitempty"`
+	Service   []*string `json:"service,omitempty"`
+}
+
+// A statement of a key policy.
+type KeyPolicyStatement struct {
+	// The KMS actions, such as kms:Encrypt, the statement allows or denies.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Actions    []*string             `json:"actions"`
+	Conditions []*KeyPolicyCondition `json:"conditions,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Allow;Deny
+	Effect *string `json:"effect"`
+	// +kubebuilder:validation:Required
+	Principals *KeyPolicyPrincipals `json:"principals"`
+	// The resources the statement applies to. In a key policy, the resource is
+	// always the KMS key itself, so the default value is "*".
+	Resources []*string `json:"resources,omitempty"`
+	SID       *string   `json:"sid,omitempty"`
+}
@@ -169,6 +169,7 @@ type MultiRegionKey struct {
 type RotationsListEntry struct {
 	KeyID        *string      `json:"keyID,omitempty"`
 	RotationDate *metav1.Time `json:"rotationDate,omitempty"`
+	RotationType *string      `json:"rotationType,omitempty"`
 }
 
 // A key-value pair. A tag consists of a tag key and a tag value. Tag keys and
@@ -184,3 +185,29 @@ type Tag struct {
 	TagKey   *string `json:"tagKey,omitempty"`
 	TagValue *string `json:"tagValue,omitempty"`
 }
+
+// KMS uses the authentication credential to sign requests that it sends to
+// the external key store proxy (XKS proxy) on your behalf. You establish these
+// credentials on your external key store proxy and report them to KMS.
+//
+// The XksProxyAuthenticationCredential includes two required elements.
+type XksProxyAuthenticationCredentialType struct {
+	AccessKeyID        *string                         `json:"accessKeyID,omitempty"`
+	RawSecretAccessKey *ackv1alpha1.SecretKeyReference `json:"rawSecretAccessKey,omitempty"`
+}
+
+// Information about the external key (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html#concept-external-key)
+// that is associated with a KMS key in an external key store.
+//
+// This element appears in a CreateKey or DescribeKey response only for a KMS
+// key in an external key store.
+//
+// The external key is a symmetric encryption key that is hosted by an external
+// key manager outside of Amazon Web Services. When you use the KMS key in an
+// external key store in a cryptographic operation, the cryptographic operation
+// is performed in the external key manager using the specified external key.
+// For more information, see External key (https://docs.aws.amazon.com/kms/latest/developerguide/keystore-external.html#concept-external-key)
+// in the Key Management Service Developer Guide.
+type XksKeyConfigurationType struct {
+	ID *string `json:"id,omitempty"`
+}
@@ -182,6 +182,185 @@ func (in *AliasStatus) DeepCopy() *AliasStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomKeyStore) DeepCopyInto(out *CustomKeyStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomKeyStore.
+func (in *CustomKeyStore) DeepCopy() *CustomKeyStore {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomKeyStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CustomKeyStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomKeyStoreList) DeepCopyInto(out *CustomKeyStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CustomKeyStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomKeyStoreList.
+func (in *CustomKeyStoreList) DeepCopy() *CustomKeyStoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomKeyStoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CustomKeyStoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomKeyStoreSpec) DeepCopyInto(out *CustomKeyStoreSpec) {
+	*out = *in
+	if in.CloudHsmClusterID != nil {
+		in, out := &in.CloudHsmClusterID, &out.CloudHsmClusterID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Connected != nil {
+		in, out := &in.Connected, &out.Connected
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CustomKeyStoreType != nil {
+		in, out := &in.CustomKeyStoreType, &out.CustomKeyStoreType
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyStorePassword != nil {
+		in, out := &in.KeyStorePassword, &out.KeyStorePassword
+		*out = new(corev1alpha1.SecretKeyReference)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrustAnchorCertificate != nil {
+		in, out := &in.TrustAnchorCertificate, &out.TrustAnchorCertificate
+		*out = new(string)
+		**out = **in
+	}
+	if in.XksProxyAuthenticationCredential != nil {
+		in, out := &in.XksProxyAuthenticationCredential, &out.XksProxyAuthenticationCredential
+		*out = new(XksProxyAuthenticationCredentialType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.XksProxyConnectivity != nil {
+		in, out := &in.XksProxyConnectivity, &out.XksProxyConnectivity
+		*out = new(string)
+		**out = **in
+	}
+	if in.XksProxyURIEndpoint != nil {
+		in, out := &in.XksProxyURIEndpoint, &out.XksProxyURIEndpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.XksProxyURIPath != nil {
+		in, out := &in.XksProxyURIPath, &out.XksProxyURIPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.XksProxyVPCEndpointServiceName != nil {
+		in, out := &in.XksProxyVPCEndpointServiceName, &out.XksProxyVPCEndpointServiceName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomKeyStoreSpec.
+func (in *CustomKeyStoreSpec) DeepCopy() *CustomKeyStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomKeyStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomKeyStoreStatus) DeepCopyInto(out *CustomKeyStoreStatus) {
+	*out = *in
+	if in.ACKResourceMetadata != nil {
+		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
+		*out = new(corev1alpha1.ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*corev1alpha1.Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1alpha1.Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ConnectionErrorCode != nil {
+		in, out := &in.ConnectionErrorCode, &out.ConnectionErrorCode
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionState != nil {
+		in, out := &in.ConnectionState, &out.ConnectionState
+		*out = new(string)
+		**out = **in
+	}
+	if in.CreationDate != nil {
+		in, out := &in.CreationDate, &out.CreationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.CustomKeyStoreID != nil {
+		in, out := &in.CustomKeyStoreID, &out.CustomKeyStoreID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomKeyStoreStatus.
+func (in *CustomKeyStoreStatus) DeepCopy() *CustomKeyStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomKeyStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomKeyStoresListEntry) DeepCopyInto(out *CustomKeyStoresListEntry) {
 	*out = *in
@@ -715,120 +894,107 @@ func (in *KeyMetadata) DeepCopy() *KeyMetadata {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeySpec) DeepCopyInto(out *KeySpec) {
+func (in *KeyPolicyCondition) DeepCopyInto(out *KeyPolicyCondition) {
 	*out = *in
-	if in.BypassPolicyLockoutSafetyCheck != nil {
-		in, out := &in.BypassPolicyLockoutSafetyCheck, &out.BypassPolicyLockoutSafetyCheck
-		*out = new(bool)
-		**out = **in
-	}
-	if in.CustomKeyStoreID != nil {
-		in, out := &in.CustomKeyStoreID, &out.CustomKeyStoreID
-		*out = new(string)
-		**out = **in
-	}
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
-		**out = **in
-	}
-	if in.EnableKeyRotation != nil {
-		in, out := &in.EnableKeyRotation, &out.EnableKeyRotation
-		*out = new(bool)
-		**out = **in
-	}
-	if in.KeySpec != nil {
-		in, out := &in.KeySpec, &out.KeySpec
-		*out = new(string)
-		**out = **in
-	}
-	if in.KeyUsage != nil {
-		in, out := &in.KeyUsage, &out.KeyUsage
-		*out = new(string)
-		**out = **in
-	}
-	if in.MultiRegion != nil {
-		in, out := &in.MultiRegion, &out.MultiRegion
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Origin != nil {
-		in, out := &in.Origin, &out.Origin
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
 		*out = new(string)
 		**out = **in
 	}
-	if in.Policy != nil {
-		in, out := &in.Policy, &out.Policy
+	if in.Operator != nil {
+		in, out := &in.Operator, &out.Operator
 		*out = new(string)
 		**out = **in
 	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]*Tag, len(*in))
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]*string, len(*in))
 		for i := range *in {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Tag)
-				(*in).DeepCopyInto(*out)
+				*out = new(string)
+				**out = **in
 			}
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySpec.
-func (in *KeySpec) DeepCopy() *KeySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPolicyCondition.
+func (in *KeyPolicyCondition) DeepCopy() *KeyPolicyCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(KeySpec)
+	out := new(KeyPolicyCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
+func (in *KeyPolicyDocument) DeepCopyInto(out *KeyPolicyDocument) {
 	*out = *in
-	if in.ACKResourceMetadata != nil {
-		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
-		*out = new(corev1alpha1.ResourceMetadata)
-		(*in).DeepCopyInto(*out)
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]*corev1alpha1.Condition, len(*in))
+	if in.Statements != nil {
+		in, out := &in.Statements, &out.Statements
+		*out = make([]*KeyPolicyStatement, len(*in))
 		for i := range *in {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
-				*out = new(corev1alpha1.Condition)
+				*out = new(KeyPolicyStatement)
 				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
-	if in.AWSAccountID != nil {
-		in, out := &in.AWSAccountID, &out.AWSAccountID
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
 		*out = new(string)
 		**out = **in
 	}
-	if in.CloudHsmClusterID != nil {
-		in, out := &in.CloudHsmClusterID, &out.CloudHsmClusterID
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPolicyDocument.
+func (in *KeyPolicyDocument) DeepCopy() *KeyPolicyDocument {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyPolicyDocument)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyPolicyFragmentReference) DeepCopyInto(out *KeyPolicyFragmentReference) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
 		*out = new(string)
 		**out = **in
 	}
-	if in.CreationDate != nil {
-		in, out := &in.CreationDate, &out.CreationDate
-		*out = (*in).DeepCopy()
-	}
-	if in.DeletionDate != nil {
-		in, out := &in.DeletionDate, &out.DeletionDate
-		*out = (*in).DeepCopy()
-	}
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
 		**out = **in
 	}
-	if in.EncryptionAlgorithms != nil {
-		in, out := &in.EncryptionAlgorithms, &out.EncryptionAlgorithms
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPolicyFragmentReference.
+func (in *KeyPolicyFragmentReference) DeepCopy() *KeyPolicyFragmentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyPolicyFragmentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyPolicyPrincipals) DeepCopyInto(out *KeyPolicyPrincipals) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
 		*out = make([]*string, len(*in))
 		for i := range *in {
 			if (*in)[i] != nil {
@@ -838,15 +1004,367 @@ func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
 			}
 		}
 	}
-	if in.ExpirationModel != nil {
-		in, out := &in.ExpirationModel, &out.ExpirationModel
-		*out = new(string)
-		**out = **in
+	if in.Federated != nil {
+		in, out := &in.Federated, &out.Federated
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
 	}
-	if in.KeyID != nil {
-		in, out := &in.KeyID, &out.KeyID
-		*out = new(string)
-		**out = **in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPolicyPrincipals.
+func (in *KeyPolicyPrincipals) DeepCopy() *KeyPolicyPrincipals {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyPolicyPrincipals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyPolicyStatement) DeepCopyInto(out *KeyPolicyStatement) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*KeyPolicyCondition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KeyPolicyCondition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Effect != nil {
+		in, out := &in.Effect, &out.Effect
+		*out = new(string)
+		**out = **in
+	}
+	if in.Principals != nil {
+		in, out := &in.Principals, &out.Principals
+		*out = new(KeyPolicyPrincipals)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.SID != nil {
+		in, out := &in.SID, &out.SID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPolicyStatement.
+func (in *KeyPolicyStatement) DeepCopy() *KeyPolicyStatement {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyPolicyStatement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeySpec) DeepCopyInto(out *KeySpec) {
+	*out = *in
+	if in.BypassPolicyLockoutSafetyCheck != nil {
+		in, out := &in.BypassPolicyLockoutSafetyCheck, &out.BypassPolicyLockoutSafetyCheck
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CustomKeyStoreID != nil {
+		in, out := &in.CustomKeyStoreID, &out.CustomKeyStoreID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CustomKeyStoreRef != nil {
+		in, out := &in.CustomKeyStoreRef, &out.CustomKeyStoreRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionProtectionEnabled != nil {
+		in, out := &in.DeletionProtectionEnabled, &out.DeletionProtectionEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnableKeyRotation != nil {
+		in, out := &in.EnableKeyRotation, &out.EnableKeyRotation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EncryptedKeyMaterial != nil {
+		in, out := &in.EncryptedKeyMaterial, &out.EncryptedKeyMaterial
+		*out = new(corev1alpha1.SecretKeyReference)
+		**out = **in
+	}
+	if in.ImportExpirationModel != nil {
+		in, out := &in.ImportExpirationModel, &out.ImportExpirationModel
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImportValidTo != nil {
+		in, out := &in.ImportValidTo, &out.ImportValidTo
+		*out = (*in).DeepCopy()
+	}
+	if in.ImportValidityInDays != nil {
+		in, out := &in.ImportValidityInDays, &out.ImportValidityInDays
+		*out = new(int64)
+		**out = **in
+	}
+	if in.KeyMaterialExpiryWarningDays != nil {
+		in, out := &in.KeyMaterialExpiryWarningDays, &out.KeyMaterialExpiryWarningDays
+		*out = make([]*int64, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(int64)
+				**out = **in
+			}
+		}
+	}
+	if in.KeySpec != nil {
+		in, out := &in.KeySpec, &out.KeySpec
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyUsage != nil {
+		in, out := &in.KeyUsage, &out.KeyUsage
+		*out = new(string)
+		**out = **in
+	}
+	if in.MultiRegion != nil {
+		in, out := &in.MultiRegion, &out.MultiRegion
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OnDemandRotationGeneration != nil {
+		in, out := &in.OnDemandRotationGeneration, &out.OnDemandRotationGeneration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Origin != nil {
+		in, out := &in.Origin, &out.Origin
+		*out = new(string)
+		**out = **in
+	}
+	if in.PendingWindowInDays != nil {
+		in, out := &in.PendingWindowInDays, &out.PendingWindowInDays
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(string)
+		**out = **in
+	}
+	if in.PolicyDocument != nil {
+		in, out := &in.PolicyDocument, &out.PolicyDocument
+		*out = new(KeyPolicyDocument)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyDriftMode != nil {
+		in, out := &in.PolicyDriftMode, &out.PolicyDriftMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]*KeyPolicyFragmentReference, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KeyPolicyFragmentReference)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.PrimaryRegion != nil {
+		in, out := &in.PrimaryRegion, &out.PrimaryRegion
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReimportKeyMaterialBeforeExpiryInDays != nil {
+		in, out := &in.ReimportKeyMaterialBeforeExpiryInDays, &out.ReimportKeyMaterialBeforeExpiryInDays
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RestoreDefaultPolicy != nil {
+		in, out := &in.RestoreDefaultPolicy, &out.RestoreDefaultPolicy
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RotationPeriodInDays != nil {
+		in, out := &in.RotationPeriodInDays, &out.RotationPeriodInDays
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]*Tag, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Tag)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.WrappingAlgorithm != nil {
+		in, out := &in.WrappingAlgorithm, &out.WrappingAlgorithm
+		*out = new(string)
+		**out = **in
+	}
+	if in.WrappingKeySpec != nil {
+		in, out := &in.WrappingKeySpec, &out.WrappingKeySpec
+		*out = new(string)
+		**out = **in
+	}
+	if in.XksKeyID != nil {
+		in, out := &in.XksKeyID, &out.XksKeyID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySpec.
+func (in *KeySpec) DeepCopy() *KeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
+	*out = *in
+	if in.ACKResourceMetadata != nil {
+		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
+		*out = new(corev1alpha1.ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*corev1alpha1.Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1alpha1.Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.AWSAccountID != nil {
+		in, out := &in.AWSAccountID, &out.AWSAccountID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CloudHsmClusterID != nil {
+		in, out := &in.CloudHsmClusterID, &out.CloudHsmClusterID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CreationDate != nil {
+		in, out := &in.CreationDate, &out.CreationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.DeletionDate != nil {
+		in, out := &in.DeletionDate, &out.DeletionDate
+		*out = (*in).DeepCopy()
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EncryptionAlgorithms != nil {
+		in, out := &in.EncryptionAlgorithms, &out.EncryptionAlgorithms
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.ExpirationModel != nil {
+		in, out := &in.ExpirationModel, &out.ExpirationModel
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImportParametersValidTo != nil {
+		in, out := &in.ImportParametersValidTo, &out.ImportParametersValidTo
+		*out = (*in).DeepCopy()
+	}
+	if in.ImportToken != nil {
+		in, out := &in.ImportToken, &out.ImportToken
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyAgreementAlgorithms != nil {
+		in, out := &in.KeyAgreementAlgorithms, &out.KeyAgreementAlgorithms
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.KeyID != nil {
+		in, out := &in.KeyID, &out.KeyID
+		*out = new(string)
+		**out = **in
 	}
 	if in.KeyManager != nil {
 		in, out := &in.KeyManager, &out.KeyManager
@@ -858,6 +1376,11 @@ func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.LastOnDemandRotationGeneration != nil {
+		in, out := &in.LastOnDemandRotationGeneration, &out.LastOnDemandRotationGeneration
+		*out = new(int64)
+		**out = **in
+	}
 	if in.MacAlgorithms != nil {
 		in, out := &in.MacAlgorithms, &out.MacAlgorithms
 		*out = make([]*string, len(*in))
@@ -874,11 +1397,51 @@ func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
 		*out = new(MultiRegionConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NextRotationDate != nil {
+		in, out := &in.NextRotationDate, &out.NextRotationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.OnDemandRotationStartDate != nil {
+		in, out := &in.OnDemandRotationStartDate, &out.OnDemandRotationStartDate
+		*out = (*in).DeepCopy()
+	}
 	if in.PendingDeletionWindowInDays != nil {
 		in, out := &in.PendingDeletionWindowInDays, &out.PendingDeletionWindowInDays
 		*out = new(int64)
 		**out = **in
 	}
+	if in.PolicyApplied != nil {
+		in, out := &in.PolicyApplied, &out.PolicyApplied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PolicyNames != nil {
+		in, out := &in.PolicyNames, &out.PolicyNames
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.PublicKey != nil {
+		in, out := &in.PublicKey, &out.PublicKey
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecentRotations != nil {
+		in, out := &in.RecentRotations, &out.RecentRotations
+		*out = make([]*RotationsListEntry, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(RotationsListEntry)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.SigningAlgorithms != nil {
 		in, out := &in.SigningAlgorithms, &out.SigningAlgorithms
 		*out = make([]*string, len(*in))
@@ -894,6 +1457,11 @@ func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
 		in, out := &in.ValidTo, &out.ValidTo
 		*out = (*in).DeepCopy()
 	}
+	if in.XksKeyConfiguration != nil {
+		in, out := &in.XksKeyConfiguration, &out.XksKeyConfiguration
+		*out = new(XksKeyConfigurationType)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyStatus.
@@ -967,6 +1535,190 @@ func (in *MultiRegionKey) DeepCopy() *MultiRegionKey {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaKey) DeepCopyInto(out *ReplicaKey) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaKey.
+func (in *ReplicaKey) DeepCopy() *ReplicaKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicaKey) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaKeyList) DeepCopyInto(out *ReplicaKeyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ReplicaKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaKeyList.
+func (in *ReplicaKeyList) DeepCopy() *ReplicaKeyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaKeyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicaKeyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaKeySpec) DeepCopyInto(out *ReplicaKeySpec) {
+	*out = *in
+	if in.BypassPolicyLockoutSafetyCheck != nil {
+		in, out := &in.BypassPolicyLockoutSafetyCheck, &out.BypassPolicyLockoutSafetyCheck
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyID != nil {
+		in, out := &in.KeyID, &out.KeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyRef != nil {
+		in, out := &in.KeyRef, &out.KeyRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingWindowInDays != nil {
+		in, out := &in.PendingWindowInDays, &out.PendingWindowInDays
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]*Tag, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Tag)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaKeySpec.
+func (in *ReplicaKeySpec) DeepCopy() *ReplicaKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaKeyStatus) DeepCopyInto(out *ReplicaKeyStatus) {
+	*out = *in
+	if in.ACKResourceMetadata != nil {
+		in, out := &in.ACKResourceMetadata, &out.ACKResourceMetadata
+		*out = new(corev1alpha1.ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*corev1alpha1.Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1alpha1.Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.CreationDate != nil {
+		in, out := &in.CreationDate, &out.CreationDate
+		*out = (*in).DeepCopy()
+	}
+	if in.DeletionDate != nil {
+		in, out := &in.DeletionDate, &out.DeletionDate
+		*out = (*in).DeepCopy()
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KeyID != nil {
+		in, out := &in.KeyID, &out.KeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeySpec != nil {
+		in, out := &in.KeySpec, &out.KeySpec
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyState != nil {
+		in, out := &in.KeyState, &out.KeyState
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyUsage != nil {
+		in, out := &in.KeyUsage, &out.KeyUsage
+		*out = new(string)
+		**out = **in
+	}
+	if in.MultiRegionConfiguration != nil {
+		in, out := &in.MultiRegionConfiguration, &out.MultiRegionConfiguration
+		*out = new(MultiRegionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaKeyStatus.
+func (in *ReplicaKeyStatus) DeepCopy() *ReplicaKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RotationsListEntry) DeepCopyInto(out *RotationsListEntry) {
 	*out = *in
@@ -979,6 +1731,11 @@ func (in *RotationsListEntry) DeepCopyInto(out *RotationsListEntry) {
 		in, out := &in.RotationDate, &out.RotationDate
 		*out = (*in).DeepCopy()
 	}
+	if in.RotationType != nil {
+		in, out := &in.RotationType, &out.RotationType
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotationsListEntry.
@@ -1015,3 +1772,48 @@ func (in *Tag) DeepCopy() *Tag {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XksKeyConfigurationType) DeepCopyInto(out *XksKeyConfigurationType) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XksKeyConfigurationType.
+func (in *XksKeyConfigurationType) DeepCopy() *XksKeyConfigurationType {
+	if in == nil {
+		return nil
+	}
+	out := new(XksKeyConfigurationType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XksProxyAuthenticationCredentialType) DeepCopyInto(out *XksProxyAuthenticationCredentialType) {
+	*out = *in
+	if in.AccessKeyID != nil {
+		in, out := &in.AccessKeyID, &out.AccessKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RawSecretAccessKey != nil {
+		in, out := &in.RawSecretAccessKey, &out.RawSecretAccessKey
+		*out = new(corev1alpha1.SecretKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XksProxyAuthenticationCredentialType.
+func (in *XksProxyAuthenticationCredentialType) DeepCopy() *XksProxyAuthenticationCredentialType {
+	if in == nil {
+		return nil
+	}
+	out := new(XksProxyAuthenticationCredentialType)
+	in.DeepCopyInto(out)
+	return out
+}
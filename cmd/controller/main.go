@@ -42,8 +42,10 @@ import (
 	svcresource "github.com/aws-controllers-k8s/kms-controller/pkg/resource"
 
 	_ "github.com/aws-controllers-k8s/kms-controller/pkg/resource/alias"
+	_ "github.com/aws-controllers-k8s/kms-controller/pkg/resource/custom_key_store"
 	_ "github.com/aws-controllers-k8s/kms-controller/pkg/resource/grant"
-	_ "github.com/aws-controllers-k8s/kms-controller/pkg/resource/key"
+	keyresource "github.com/aws-controllers-k8s/kms-controller/pkg/resource/key"
+	_ "github.com/aws-controllers-k8s/kms-controller/pkg/resource/replica_key"
 
 	"github.com/aws-controllers-k8s/kms-controller/pkg/version"
 )
@@ -209,6 +211,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = keyresource.SetupPolicyRefsWatcher(mgr, sc); err != nil {
+		setupLog.Error(
+			err, "unable to set up key policy fragment watcher",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
 	if err = mgr.AddHealthzCheck("health", ctrlrthealthz.Ping); err != nil {
 		setupLog.Error(
 			err, "unable to set up health check",
@@ -73,8 +73,10 @@ rules:
   - kms.services.k8s.aws
   resources:
   - aliases
+  - customkeystores
   - grants
   - keys
+  - replicakeys
   verbs:
   - create
   - delete
@@ -87,8 +89,10 @@ rules:
   - kms.services.k8s.aws
   resources:
   - aliases/status
+  - customkeystores/status
   - grants/status
   - keys/status
+  - replicakeys/status
   verbs:
   - get
   - patch
@@ -0,0 +1,105 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package testutil contains the helpers shared by the unit tests of the
+// resource managers.
+package testutil
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// FakeSDKAPI short-circuits every KMS operation issued through the client
+// returned by NewClient, and every operation issued through a client built
+// from the configuration returned by NewClientConfig. Calls are recorded in
+// order and answered from the canned outputs and errors keyed by operation
+// name; KMS operations without a canned output succeed with an empty output.
+type FakeSDKAPI struct {
+	// Calls contains the names of the invoked operations, in order
+	Calls []string
+	// Inputs contains the input of the last call of each operation
+	Inputs map[string]interface{}
+	// Regions contains the Region of the last call of each operation
+	Regions map[string]string
+	// Outputs contains the canned output of each operation
+	Outputs map[string]interface{}
+	// Errors contains the canned error of each operation
+	Errors map[string]error
+}
+
+// NewFakeSDKAPI returns a FakeSDKAPI without canned outputs or errors.
+func NewFakeSDKAPI() *FakeSDKAPI {
+	return &FakeSDKAPI{
+		Inputs:  map[string]interface{}{},
+		Regions: map[string]string{},
+		Outputs: map[string]interface{}{},
+		Errors:  map[string]error{},
+	}
+}
+
+// NewClient returns a KMS client whose requests never leave the process.
+func (f *FakeSDKAPI) NewClient() *svcsdk.Client {
+	return svcsdk.NewFromConfig(f.NewClientConfig())
+}
+
+// NewClientConfig returns a client configuration whose requests never leave
+// the process.
+func (f *FakeSDKAPI) NewClientConfig() aws.Config {
+	return aws.Config{
+		Region: "us-west-2",
+		APIOptions: []func(*middleware.Stack) error{
+			func(stack *middleware.Stack) error {
+				return stack.Initialize.Add(
+					middleware.InitializeMiddlewareFunc("fakeSDKAPI", f.handle),
+					middleware.After,
+				)
+			},
+		},
+	}
+}
+
+func (f *FakeSDKAPI) handle(
+	ctx context.Context,
+	in middleware.InitializeInput,
+	next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	op := middleware.GetOperationName(ctx)
+	f.Calls = append(f.Calls, op)
+	f.Inputs[op] = in.Parameters
+	f.Regions[op] = awsmiddleware.GetRegion(ctx)
+	if err, ok := f.Errors[op]; ok {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, err
+	}
+	out, ok := f.Outputs[op]
+	if !ok {
+		method, _ := reflect.TypeOf(&svcsdk.Client{}).MethodByName(op)
+		out = reflect.New(method.Type.Out(0).Elem()).Interface()
+	}
+	return middleware.InitializeOutput{Result: out}, middleware.Metadata{}, nil
+}
+
+// Called returns true if the supplied operation was invoked at least once.
+func (f *FakeSDKAPI) Called(op string) bool {
+	for _, c := range f.Calls {
+		if c == op {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,8 @@ package alias
 
 import (
 	"strings"
+
+	kmsutil "github.com/aws-controllers-k8s/kms-controller/pkg/util"
 )
 
 const AliasPrefix = "alias/"
@@ -34,3 +36,10 @@ func ensureAliasName(name *string) *string {
 	}
 	return &nameVal
 }
+
+// requeueOnThrottling turns the KMS throttling error err points to into a
+// requeue error, see kmsutil.RequeueOnThrottling. The operations of the
+// resource manager defer it.
+func requeueOnThrottling(err *error) {
+	*err = kmsutil.RequeueOnThrottling(*err)
+}
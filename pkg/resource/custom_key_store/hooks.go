@@ -0,0 +1,334 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package custom_key_store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+	kmsutil "github.com/aws-controllers-k8s/kms-controller/pkg/util"
+)
+
+var (
+	// requeueWaitWhileConnectionPending is returned while KMS is connecting
+	// or disconnecting the custom key store, which cannot be modified until
+	// the operation completes.
+	requeueWaitWhileConnectionPending = ackrequeue.NeededAfter(
+		fmt.Errorf("custom key store is connecting or disconnecting, cannot be modified"),
+		10*time.Second,
+	)
+	// requeueWaitWhileDisconnecting is returned after the custom key store
+	// was disconnected so that it can be updated or deleted.
+	requeueWaitWhileDisconnecting = ackrequeue.NeededAfter(
+		fmt.Errorf("custom key store is %s", svcsdktypes.ConnectionStateTypeDisconnecting),
+		10*time.Second,
+	)
+)
+
+// disconnectedProperties are the fields of the custom key store that KMS only
+// lets you update while the custom key store is disconnected. The name, the
+// XKS proxy authentication credential and the XKS proxy URI path can be
+// updated at any time.
+var disconnectedProperties = []string{
+	"Spec.CloudHsmClusterID",
+	"Spec.KeyStorePassword",
+	"Spec.XksProxyConnectivity",
+	"Spec.XksProxyURIEndpoint",
+	"Spec.XksProxyVPCEndpointServiceName",
+}
+
+// connectionState returns the connection state of the custom key store, or
+// an empty string if it is unknown.
+func connectionState(ko *svcapitypes.CustomKeyStore) svcsdktypes.ConnectionStateType {
+	if ko.Status.ConnectionState == nil {
+		return ""
+	}
+	return svcsdktypes.ConnectionStateType(*ko.Status.ConnectionState)
+}
+
+// setConnectionState records the supplied connection state in the status of
+// the custom key store.
+func setConnectionState(
+	ko *svcapitypes.CustomKeyStore,
+	state svcsdktypes.ConnectionStateType,
+) {
+	ko.Status.ConnectionState = aws.String(string(state))
+}
+
+// setXksProxyConfiguration copies the XKS proxy configuration of the custom
+// key store described in stores into the Spec of the resource. The secret
+// access key is never returned by KMS, only the access key ID is.
+func setXksProxyConfiguration(
+	ko *svcapitypes.CustomKeyStore,
+	stores []svcsdktypes.CustomKeyStoresListEntry,
+) {
+	var config *svcsdktypes.XksProxyConfigurationType
+	for _, store := range stores {
+		if store.CustomKeyStoreId != nil && ko.Status.CustomKeyStoreID != nil &&
+			*store.CustomKeyStoreId == *ko.Status.CustomKeyStoreID {
+			config = store.XksProxyConfiguration
+			break
+		}
+	}
+	if config == nil {
+		ko.Spec.XksProxyConnectivity = nil
+		ko.Spec.XksProxyURIEndpoint = nil
+		ko.Spec.XksProxyURIPath = nil
+		ko.Spec.XksProxyVPCEndpointServiceName = nil
+		return
+	}
+	if config.AccessKeyId != nil {
+		if ko.Spec.XksProxyAuthenticationCredential == nil {
+			ko.Spec.XksProxyAuthenticationCredential = &svcapitypes.XksProxyAuthenticationCredentialType{}
+		}
+		ko.Spec.XksProxyAuthenticationCredential.AccessKeyID = config.AccessKeyId
+	}
+	if config.Connectivity != "" {
+		ko.Spec.XksProxyConnectivity = aws.String(string(config.Connectivity))
+	} else {
+		ko.Spec.XksProxyConnectivity = nil
+	}
+	ko.Spec.XksProxyURIEndpoint = config.UriEndpoint
+	ko.Spec.XksProxyURIPath = config.UriPath
+	ko.Spec.XksProxyVPCEndpointServiceName = config.VpcEndpointServiceName
+}
+
+// setConnected sets Spec.Connected of the latest resource from its connection
+// state, so that a difference with the desired resource triggers a connect
+// or disconnect. When Spec.Connected is not set in the desired resource the
+// connection is not managed. A failed connection keeps the desired value: it
+// is only retried when other fields of the custom key store change, see
+// customUpdate.
+func setConnected(
+	desired *svcapitypes.CustomKeyStore,
+	latest *svcapitypes.CustomKeyStore,
+) {
+	if desired.Spec.Connected == nil {
+		latest.Spec.Connected = nil
+		return
+	}
+	switch connectionState(latest) {
+	case svcsdktypes.ConnectionStateTypeConnected,
+		svcsdktypes.ConnectionStateTypeConnecting:
+		latest.Spec.Connected = aws.Bool(true)
+	case svcsdktypes.ConnectionStateTypeDisconnected,
+		svcsdktypes.ConnectionStateTypeDisconnecting:
+		latest.Spec.Connected = aws.Bool(false)
+	default:
+		latest.Spec.Connected = desired.Spec.Connected
+	}
+}
+
+// customUpdate updates the properties of the custom key store and then
+// connects or disconnects it according to Spec.Connected. A custom key store
+// that must be disconnected for the update, or whose connection failed, is
+// disconnected first and updated once KMS reports it as disconnected.
+func (rm *resourceManager) customUpdate(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.customUpdate")
+	defer func() {
+		exit(err)
+	}()
+	defer requeueOnThrottling(&err)
+	state := connectionState(latest.ko)
+	if state == svcsdktypes.ConnectionStateTypeConnecting ||
+		state == svcsdktypes.ConnectionStateTypeDisconnecting {
+		return latest, requeueWaitWhileConnectionPending
+	}
+
+	updatedRes := rm.concreteResource(desired.DeepCopy())
+	updatedRes.SetStatus(latest)
+	connect := updatedRes.ko.Spec.Connected != nil && *updatedRes.ko.Spec.Connected
+
+	if delta.DifferentExcept("Spec.Connected") {
+		mustDisconnect := false
+		for _, field := range disconnectedProperties {
+			if delta.DifferentAt(field) {
+				mustDisconnect = true
+				break
+			}
+		}
+		if state == svcsdktypes.ConnectionStateTypeFailed && connect {
+			mustDisconnect = true
+		}
+		if mustDisconnect && state != svcsdktypes.ConnectionStateTypeDisconnected {
+			if err = rm.disconnect(ctx, updatedRes); err != nil {
+				return updatedRes, err
+			}
+			return updatedRes, requeueWaitWhileDisconnecting
+		}
+		if err = rm.updateCustomKeyStore(ctx, updatedRes, delta); err != nil {
+			return updatedRes, err
+		}
+	}
+	if delta.DifferentAt("Spec.Connected") && updatedRes.ko.Spec.Connected != nil {
+		if connect {
+			err = rm.connect(ctx, updatedRes)
+		} else {
+			err = rm.disconnect(ctx, updatedRes)
+		}
+		if err != nil {
+			return updatedRes, err
+		}
+	}
+	rm.setStatusDefaults(updatedRes.ko)
+	return updatedRes, nil
+}
+
+// updateCustomKeyStore performs the UpdateCustomKeyStore API call with the
+// fields of the resource that differ from the latest observed state.
+func (rm *resourceManager) updateCustomKeyStore(
+	ctx context.Context,
+	r *resource,
+	delta *ackcompare.Delta,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateCustomKeyStore")
+	defer func() {
+		exit(err)
+	}()
+	input := &svcsdk.UpdateCustomKeyStoreInput{
+		CustomKeyStoreId: r.ko.Status.CustomKeyStoreID,
+	}
+	needsUpdate := false
+	if delta.DifferentAt("Spec.Name") {
+		input.NewCustomKeyStoreName = r.ko.Spec.Name
+		needsUpdate = true
+	}
+	if delta.DifferentAt("Spec.CloudHsmClusterID") && r.ko.Spec.CloudHsmClusterID != nil {
+		input.CloudHsmClusterId = r.ko.Spec.CloudHsmClusterID
+		needsUpdate = true
+	}
+	if delta.DifferentAt("Spec.KeyStorePassword") && r.ko.Spec.KeyStorePassword != nil {
+		password, err := rm.rr.SecretValueFromReference(ctx, r.ko.Spec.KeyStorePassword)
+		if err != nil {
+			return ackrequeue.Needed(err)
+		}
+		input.KeyStorePassword = aws.String(password)
+		needsUpdate = true
+	}
+	// KMS requires both the access key ID and the secret access key whenever
+	// the authentication credential is updated.
+	credential := r.ko.Spec.XksProxyAuthenticationCredential
+	if delta.DifferentAt("Spec.XksProxyAuthenticationCredential") &&
+		credential != nil && credential.RawSecretAccessKey != nil {
+		secretAccessKey, err := rm.rr.SecretValueFromReference(ctx, credential.RawSecretAccessKey)
+		if err != nil {
+			return ackrequeue.Needed(err)
+		}
+		input.XksProxyAuthenticationCredential = &svcsdktypes.XksProxyAuthenticationCredentialType{
+			AccessKeyId:        credential.AccessKeyID,
+			RawSecretAccessKey: aws.String(secretAccessKey),
+		}
+		needsUpdate = true
+	}
+	if delta.DifferentAt("Spec.XksProxyConnectivity") && r.ko.Spec.XksProxyConnectivity != nil {
+		input.XksProxyConnectivity = svcsdktypes.XksProxyConnectivityType(*r.ko.Spec.XksProxyConnectivity)
+		needsUpdate = true
+	}
+	if delta.DifferentAt("Spec.XksProxyURIEndpoint") && r.ko.Spec.XksProxyURIEndpoint != nil {
+		input.XksProxyUriEndpoint = r.ko.Spec.XksProxyURIEndpoint
+		needsUpdate = true
+	}
+	if delta.DifferentAt("Spec.XksProxyURIPath") && r.ko.Spec.XksProxyURIPath != nil {
+		input.XksProxyUriPath = r.ko.Spec.XksProxyURIPath
+		needsUpdate = true
+	}
+	if delta.DifferentAt("Spec.XksProxyVPCEndpointServiceName") && r.ko.Spec.XksProxyVPCEndpointServiceName != nil {
+		input.XksProxyVpcEndpointServiceName = r.ko.Spec.XksProxyVPCEndpointServiceName
+		needsUpdate = true
+	}
+	if !needsUpdate {
+		return nil
+	}
+	_, err = rm.sdkapi.UpdateCustomKeyStore(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "UpdateCustomKeyStore", err)
+	return err
+}
+
+// connect performs the ConnectCustomKeyStore API call. KMS connects the
+// custom key store asynchronously, the resource is CONNECTING until it does.
+func (rm *resourceManager) connect(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.connect")
+	defer func() {
+		exit(err)
+	}()
+	_, err = rm.sdkapi.ConnectCustomKeyStore(ctx, &svcsdk.ConnectCustomKeyStoreInput{
+		CustomKeyStoreId: r.ko.Status.CustomKeyStoreID,
+	})
+	rm.metrics.RecordAPICall("UPDATE", "ConnectCustomKeyStore", err)
+	if err != nil {
+		return err
+	}
+	setConnectionState(r.ko, svcsdktypes.ConnectionStateTypeConnecting)
+	return nil
+}
+
+// disconnect performs the DisconnectCustomKeyStore API call. The resource is
+// DISCONNECTING until KMS reports the custom key store as disconnected.
+func (rm *resourceManager) disconnect(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.disconnect")
+	defer func() {
+		exit(err)
+	}()
+	_, err = rm.sdkapi.DisconnectCustomKeyStore(ctx, &svcsdk.DisconnectCustomKeyStoreInput{
+		CustomKeyStoreId: r.ko.Status.CustomKeyStoreID,
+	})
+	rm.metrics.RecordAPICall("UPDATE", "DisconnectCustomKeyStore", err)
+	if err != nil {
+		return err
+	}
+	setConnectionState(r.ko, svcsdktypes.ConnectionStateTypeDisconnecting)
+	return nil
+}
+
+// disconnectBeforeDelete makes sure the custom key store is disconnected,
+// which KMS requires before it can be deleted. It returns a requeue error
+// until KMS reports the custom key store as disconnected.
+func (rm *resourceManager) disconnectBeforeDelete(ctx context.Context, r *resource) error {
+	switch connectionState(r.ko) {
+	case "", svcsdktypes.ConnectionStateTypeDisconnected:
+		return nil
+	case svcsdktypes.ConnectionStateTypeConnecting,
+		svcsdktypes.ConnectionStateTypeDisconnecting:
+		return requeueWaitWhileConnectionPending
+	}
+	if err := rm.disconnect(ctx, r); err != nil {
+		return err
+	}
+	return requeueWaitWhileDisconnecting
+}
+
+// requeueOnThrottling turns the KMS throttling error err points to into a
+// requeue error, see kmsutil.RequeueOnThrottling. The operations of the
+// resource manager defer it.
+func requeueOnThrottling(err *error) {
+	*err = kmsutil.RequeueOnThrottling(*err)
+}
@@ -0,0 +1,336 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package custom_key_store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/kms-controller/pkg/internal/testutil"
+)
+
+const testCustomKeyStoreID = "cks-1234567890abcdef0"
+
+// fakeReconciler stores the Secrets of a single namespace in memory, keyed
+// by name.
+type fakeReconciler struct {
+	acktypes.Reconciler
+	secrets map[string]map[string]string
+}
+
+func (r *fakeReconciler) SecretValueFromReference(
+	ctx context.Context,
+	ref *ackv1alpha1.SecretKeyReference,
+) (string, error) {
+	if value, ok := r.secrets[ref.Name][ref.Key]; ok {
+		return value, nil
+	}
+	return "", ackerr.SecretNotFound
+}
+
+func newXksCustomKeyStore(state svcsdktypes.ConnectionStateType) *resource {
+	r := &resource{&svcapitypes.CustomKeyStore{
+		Spec: svcapitypes.CustomKeyStoreSpec{
+			Connected:          aws.Bool(true),
+			CustomKeyStoreType: aws.String(string(svcsdktypes.CustomKeyStoreTypeExternalKeyStore)),
+			Name:               aws.String("xks"),
+			XksProxyAuthenticationCredential: &svcapitypes.XksProxyAuthenticationCredentialType{
+				AccessKeyID: aws.String("ACCESSKEYID"),
+				RawSecretAccessKey: &ackv1alpha1.SecretKeyReference{
+					SecretReference: corev1.SecretReference{Name: "xks-proxy"},
+					Key:             "secretAccessKey",
+				},
+			},
+			XksProxyConnectivity: aws.String(string(svcsdktypes.XksProxyConnectivityTypePublicEndpoint)),
+			XksProxyURIEndpoint:  aws.String("https://xks.example.com"),
+			XksProxyURIPath:      aws.String("/kms/xks/v1"),
+		},
+	}}
+	if state != "" {
+		r.ko.Status.CustomKeyStoreID = aws.String(testCustomKeyStoreID)
+		setConnectionState(r.ko, state)
+	}
+	return r
+}
+
+func newTestResourceManager(f *testutil.FakeSDKAPI) *resourceManager {
+	rm := newFakeResourceManager(f)
+	rm.rr = &fakeReconciler{secrets: map[string]map[string]string{
+		"xks-proxy": {"secretAccessKey": "SECRETACCESSKEY"},
+	}}
+	return rm
+}
+
+func TestSdkCreate_ReadsSecrets(t *testing.T) {
+	f := testutil.NewFakeSDKAPI()
+	f.Outputs["CreateCustomKeyStore"] = &svcsdk.CreateCustomKeyStoreOutput{
+		CustomKeyStoreId: aws.String(testCustomKeyStoreID),
+	}
+	rm := newTestResourceManager(f)
+
+	created, err := rm.sdkCreate(context.TODO(), newXksCustomKeyStore(""))
+	require.NoError(t, err)
+
+	input := f.Inputs["CreateCustomKeyStore"].(*svcsdk.CreateCustomKeyStoreInput)
+	assert.Equal(t, "xks", *input.CustomKeyStoreName)
+	assert.Equal(t, svcsdktypes.CustomKeyStoreTypeExternalKeyStore, input.CustomKeyStoreType)
+	assert.Equal(t, "ACCESSKEYID", *input.XksProxyAuthenticationCredential.AccessKeyId)
+	assert.Equal(t, "SECRETACCESSKEY", *input.XksProxyAuthenticationCredential.RawSecretAccessKey)
+	assert.Equal(t, testCustomKeyStoreID, *created.ko.Status.CustomKeyStoreID)
+	// The custom key store is connected by the next update, once it has been
+	// read back as DISCONNECTED.
+	assert.False(t, f.Called("ConnectCustomKeyStore"))
+}
+
+func TestSdkFind(t *testing.T) {
+	tests := []struct {
+		name              string
+		state             svcsdktypes.ConnectionStateType
+		desiredConnected  *bool
+		expectedConnected *bool
+	}{
+		{
+			name:              "connected",
+			state:             svcsdktypes.ConnectionStateTypeConnected,
+			desiredConnected:  aws.Bool(false),
+			expectedConnected: aws.Bool(true),
+		},
+		{
+			name:              "disconnected",
+			state:             svcsdktypes.ConnectionStateTypeDisconnected,
+			desiredConnected:  aws.Bool(true),
+			expectedConnected: aws.Bool(false),
+		},
+		{
+			name:              "failed keeps the desired value",
+			state:             svcsdktypes.ConnectionStateTypeFailed,
+			desiredConnected:  aws.Bool(true),
+			expectedConnected: aws.Bool(true),
+		},
+		{
+			name:  "connection not managed",
+			state: svcsdktypes.ConnectionStateTypeConnected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			f.Outputs["DescribeCustomKeyStores"] = &svcsdk.DescribeCustomKeyStoresOutput{
+				CustomKeyStores: []svcsdktypes.CustomKeyStoresListEntry{{
+					ConnectionState:    tt.state,
+					CustomKeyStoreId:   aws.String(testCustomKeyStoreID),
+					CustomKeyStoreName: aws.String("xks"),
+					CustomKeyStoreType: svcsdktypes.CustomKeyStoreTypeExternalKeyStore,
+					XksProxyConfiguration: &svcsdktypes.XksProxyConfigurationType{
+						AccessKeyId:  aws.String("OTHERACCESSKEYID"),
+						Connectivity: svcsdktypes.XksProxyConnectivityTypePublicEndpoint,
+						UriEndpoint:  aws.String("https://xks.example.com"),
+						UriPath:      aws.String("/example/kms/xks/v1"),
+					},
+				}},
+			}
+			rm := newTestResourceManager(f)
+			desired := newXksCustomKeyStore(svcsdktypes.ConnectionStateTypeConnected)
+			desired.ko.Spec.Connected = tt.desiredConnected
+
+			latest, err := rm.sdkFind(context.TODO(), desired)
+			require.NoError(t, err)
+
+			input := f.Inputs["DescribeCustomKeyStores"].(*svcsdk.DescribeCustomKeyStoresInput)
+			assert.Equal(t, testCustomKeyStoreID, *input.CustomKeyStoreId)
+			assert.Equal(t, string(tt.state), *latest.ko.Status.ConnectionState)
+			assert.Equal(t, tt.expectedConnected, latest.ko.Spec.Connected)
+			credential := latest.ko.Spec.XksProxyAuthenticationCredential
+			assert.Equal(t, "OTHERACCESSKEYID", *credential.AccessKeyID)
+			assert.Equal(t, "xks-proxy", credential.RawSecretAccessKey.Name)
+			assert.Equal(t, "/example/kms/xks/v1", *latest.ko.Spec.XksProxyURIPath)
+		})
+	}
+}
+
+func TestSdkFind_NotCreated(t *testing.T) {
+	f := testutil.NewFakeSDKAPI()
+	rm := newTestResourceManager(f)
+
+	_, err := rm.sdkFind(context.TODO(), newXksCustomKeyStore(""))
+
+	assert.Equal(t, ackerr.NotFound, err)
+	assert.False(t, f.Called("DescribeCustomKeyStores"))
+}
+
+func TestCustomUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         svcsdktypes.ConnectionStateType
+		connected     bool
+		change        func(ko *svcapitypes.CustomKeyStore)
+		expectRequeue bool
+		expectedCalls []string
+		expectedState svcsdktypes.ConnectionStateType
+	}{
+		{
+			name:          "connects a disconnected custom key store",
+			state:         svcsdktypes.ConnectionStateTypeDisconnected,
+			connected:     false,
+			expectedCalls: []string{"ConnectCustomKeyStore"},
+			expectedState: svcsdktypes.ConnectionStateTypeConnecting,
+		},
+		{
+			name:      "renames a connected custom key store",
+			state:     svcsdktypes.ConnectionStateTypeConnected,
+			connected: true,
+			change: func(ko *svcapitypes.CustomKeyStore) {
+				ko.Spec.Name = aws.String("renamed")
+			},
+			expectedCalls: []string{"UpdateCustomKeyStore"},
+			expectedState: svcsdktypes.ConnectionStateTypeConnected,
+		},
+		{
+			name:      "disconnects before updating the proxy endpoint",
+			state:     svcsdktypes.ConnectionStateTypeConnected,
+			connected: true,
+			change: func(ko *svcapitypes.CustomKeyStore) {
+				ko.Spec.XksProxyURIEndpoint = aws.String("https://other.example.com")
+			},
+			expectRequeue: true,
+			expectedCalls: []string{"DisconnectCustomKeyStore"},
+			expectedState: svcsdktypes.ConnectionStateTypeDisconnecting,
+		},
+		{
+			name:      "updates the proxy endpoint and reconnects",
+			state:     svcsdktypes.ConnectionStateTypeDisconnected,
+			connected: false,
+			change: func(ko *svcapitypes.CustomKeyStore) {
+				ko.Spec.XksProxyURIEndpoint = aws.String("https://other.example.com")
+			},
+			expectedCalls: []string{"UpdateCustomKeyStore", "ConnectCustomKeyStore"},
+			expectedState: svcsdktypes.ConnectionStateTypeConnecting,
+		},
+		{
+			name:      "disconnects a failed custom key store before updating it",
+			state:     svcsdktypes.ConnectionStateTypeFailed,
+			connected: true,
+			change: func(ko *svcapitypes.CustomKeyStore) {
+				ko.Spec.XksProxyURIPath = aws.String("/other/kms/xks/v1")
+			},
+			expectRequeue: true,
+			expectedCalls: []string{"DisconnectCustomKeyStore"},
+			expectedState: svcsdktypes.ConnectionStateTypeDisconnecting,
+		},
+		{
+			name:          "waits while connecting",
+			state:         svcsdktypes.ConnectionStateTypeConnecting,
+			connected:     true,
+			expectRequeue: true,
+			expectedState: svcsdktypes.ConnectionStateTypeConnecting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			rm := newTestResourceManager(f)
+			latest := newXksCustomKeyStore(tt.state)
+			latest.ko.Spec.Connected = aws.Bool(tt.connected)
+			desired := newXksCustomKeyStore(tt.state)
+			if tt.change != nil {
+				tt.change(desired.ko)
+			}
+			delta := newResourceDelta(desired, latest)
+
+			updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+
+			if tt.expectRequeue {
+				var requeueErr *ackrequeue.RequeueNeededAfter
+				assert.True(t, errors.As(err, &requeueErr))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, f.Calls)
+			assert.Equal(t, string(tt.expectedState), *updated.ko.Status.ConnectionState)
+		})
+	}
+}
+
+func TestCustomUpdate_AuthenticationCredential(t *testing.T) {
+	f := testutil.NewFakeSDKAPI()
+	rm := newTestResourceManager(f)
+	latest := newXksCustomKeyStore(svcsdktypes.ConnectionStateTypeConnected)
+	desired := newXksCustomKeyStore(svcsdktypes.ConnectionStateTypeConnected)
+	desired.ko.Spec.XksProxyAuthenticationCredential.AccessKeyID = aws.String("NEWACCESSKEYID")
+
+	_, err := rm.customUpdate(context.TODO(), desired, latest, newResourceDelta(desired, latest))
+	require.NoError(t, err)
+
+	input := f.Inputs["UpdateCustomKeyStore"].(*svcsdk.UpdateCustomKeyStoreInput)
+	assert.Equal(t, testCustomKeyStoreID, *input.CustomKeyStoreId)
+	assert.Nil(t, input.NewCustomKeyStoreName)
+	assert.Nil(t, input.XksProxyUriEndpoint)
+	assert.Equal(t, "NEWACCESSKEYID", *input.XksProxyAuthenticationCredential.AccessKeyId)
+	assert.Equal(t, "SECRETACCESSKEY", *input.XksProxyAuthenticationCredential.RawSecretAccessKey)
+}
+
+func TestSdkDelete(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         svcsdktypes.ConnectionStateType
+		expectRequeue bool
+		expectedCalls []string
+	}{
+		{
+			name:          "disconnects a connected custom key store first",
+			state:         svcsdktypes.ConnectionStateTypeConnected,
+			expectRequeue: true,
+			expectedCalls: []string{"DisconnectCustomKeyStore"},
+		},
+		{
+			name:          "waits while disconnecting",
+			state:         svcsdktypes.ConnectionStateTypeDisconnecting,
+			expectRequeue: true,
+		},
+		{
+			name:          "deletes a disconnected custom key store",
+			state:         svcsdktypes.ConnectionStateTypeDisconnected,
+			expectedCalls: []string{"DeleteCustomKeyStore"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			rm := newTestResourceManager(f)
+
+			_, err := rm.sdkDelete(context.TODO(), newXksCustomKeyStore(tt.state))
+
+			if tt.expectRequeue {
+				var requeueErr *ackrequeue.RequeueNeededAfter
+				assert.True(t, errors.As(err, &requeueErr))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, f.Calls)
+		})
+	}
+}
@@ -19,6 +19,8 @@ import (
 
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
 	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+
+	kmsutil "github.com/aws-controllers-k8s/kms-controller/pkg/util"
 )
 
 // updateNotSupported returns a terminal error because KMS grant
@@ -32,3 +34,10 @@ func (rm *resourceManager) updateNotSupported(ctx context.Context,
 		fmt.Errorf("grant resource does not support updates"),
 	)
 }
+
+// requeueOnThrottling turns the KMS throttling error err points to into a
+// requeue error, see kmsutil.RequeueOnThrottling. The operations of the
+// resource manager defer it.
+func requeueOnThrottling(err *error) {
+	*err = kmsutil.RequeueOnThrottling(*err)
+}
@@ -17,9 +17,11 @@ package key
 
 import (
 	"bytes"
+	"reflect"
 
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
 	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	"k8s.io/apimachinery/pkg/api/equality"
 )
 
 // Hack to avoid import errors during build...
@@ -40,6 +42,7 @@ func newResourceDelta(
 		delta.Add("", a, b)
 		return delta
 	}
+	customPreCompare(delta, a, b)
 
 	if ackcompare.HasNilDifference(a.ko.Spec.BypassPolicyLockoutSafetyCheck, b.ko.Spec.BypassPolicyLockoutSafetyCheck) {
 		delta.Add("Spec.BypassPolicyLockoutSafetyCheck", a.ko.Spec.BypassPolicyLockoutSafetyCheck, b.ko.Spec.BypassPolicyLockoutSafetyCheck)
@@ -55,6 +58,16 @@ func newResourceDelta(
 			delta.Add("Spec.CustomKeyStoreID", a.ko.Spec.CustomKeyStoreID, b.ko.Spec.CustomKeyStoreID)
 		}
 	}
+	if !equality.Semantic.Equalities.DeepEqual(a.ko.Spec.CustomKeyStoreRef, b.ko.Spec.CustomKeyStoreRef) {
+		delta.Add("Spec.CustomKeyStoreRef", a.ko.Spec.CustomKeyStoreRef, b.ko.Spec.CustomKeyStoreRef)
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.DeletionProtectionEnabled, b.ko.Spec.DeletionProtectionEnabled) {
+		delta.Add("Spec.DeletionProtectionEnabled", a.ko.Spec.DeletionProtectionEnabled, b.ko.Spec.DeletionProtectionEnabled)
+	} else if a.ko.Spec.DeletionProtectionEnabled != nil && b.ko.Spec.DeletionProtectionEnabled != nil {
+		if *a.ko.Spec.DeletionProtectionEnabled != *b.ko.Spec.DeletionProtectionEnabled {
+			delta.Add("Spec.DeletionProtectionEnabled", a.ko.Spec.DeletionProtectionEnabled, b.ko.Spec.DeletionProtectionEnabled)
+		}
+	}
 	if ackcompare.HasNilDifference(a.ko.Spec.Description, b.ko.Spec.Description) {
 		delta.Add("Spec.Description", a.ko.Spec.Description, b.ko.Spec.Description)
 	} else if a.ko.Spec.Description != nil && b.ko.Spec.Description != nil {
@@ -69,6 +82,44 @@ func newResourceDelta(
 			delta.Add("Spec.EnableKeyRotation", a.ko.Spec.EnableKeyRotation, b.ko.Spec.EnableKeyRotation)
 		}
 	}
+	if ackcompare.HasNilDifference(a.ko.Spec.Enabled, b.ko.Spec.Enabled) {
+		delta.Add("Spec.Enabled", a.ko.Spec.Enabled, b.ko.Spec.Enabled)
+	} else if a.ko.Spec.Enabled != nil && b.ko.Spec.Enabled != nil {
+		if *a.ko.Spec.Enabled != *b.ko.Spec.Enabled {
+			delta.Add("Spec.Enabled", a.ko.Spec.Enabled, b.ko.Spec.Enabled)
+		}
+	}
+	if !reflect.DeepEqual(a.ko.Spec.EncryptedKeyMaterial, b.ko.Spec.EncryptedKeyMaterial) {
+		delta.Add("Spec.EncryptedKeyMaterial", a.ko.Spec.EncryptedKeyMaterial, b.ko.Spec.EncryptedKeyMaterial)
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.ImportExpirationModel, b.ko.Spec.ImportExpirationModel) {
+		delta.Add("Spec.ImportExpirationModel", a.ko.Spec.ImportExpirationModel, b.ko.Spec.ImportExpirationModel)
+	} else if a.ko.Spec.ImportExpirationModel != nil && b.ko.Spec.ImportExpirationModel != nil {
+		if *a.ko.Spec.ImportExpirationModel != *b.ko.Spec.ImportExpirationModel {
+			delta.Add("Spec.ImportExpirationModel", a.ko.Spec.ImportExpirationModel, b.ko.Spec.ImportExpirationModel)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.ImportValidTo, b.ko.Spec.ImportValidTo) {
+		delta.Add("Spec.ImportValidTo", a.ko.Spec.ImportValidTo, b.ko.Spec.ImportValidTo)
+	} else if a.ko.Spec.ImportValidTo != nil && b.ko.Spec.ImportValidTo != nil {
+		if !a.ko.Spec.ImportValidTo.Equal(b.ko.Spec.ImportValidTo) {
+			delta.Add("Spec.ImportValidTo", a.ko.Spec.ImportValidTo, b.ko.Spec.ImportValidTo)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.ImportValidityInDays, b.ko.Spec.ImportValidityInDays) {
+		delta.Add("Spec.ImportValidityInDays", a.ko.Spec.ImportValidityInDays, b.ko.Spec.ImportValidityInDays)
+	} else if a.ko.Spec.ImportValidityInDays != nil && b.ko.Spec.ImportValidityInDays != nil {
+		if *a.ko.Spec.ImportValidityInDays != *b.ko.Spec.ImportValidityInDays {
+			delta.Add("Spec.ImportValidityInDays", a.ko.Spec.ImportValidityInDays, b.ko.Spec.ImportValidityInDays)
+		}
+	}
+	if len(a.ko.Spec.KeyMaterialExpiryWarningDays) != len(b.ko.Spec.KeyMaterialExpiryWarningDays) {
+		delta.Add("Spec.KeyMaterialExpiryWarningDays", a.ko.Spec.KeyMaterialExpiryWarningDays, b.ko.Spec.KeyMaterialExpiryWarningDays)
+	} else if len(a.ko.Spec.KeyMaterialExpiryWarningDays) > 0 {
+		if !reflect.DeepEqual(a.ko.Spec.KeyMaterialExpiryWarningDays, b.ko.Spec.KeyMaterialExpiryWarningDays) {
+			delta.Add("Spec.KeyMaterialExpiryWarningDays", a.ko.Spec.KeyMaterialExpiryWarningDays, b.ko.Spec.KeyMaterialExpiryWarningDays)
+		}
+	}
 	if ackcompare.HasNilDifference(a.ko.Spec.KeySpec, b.ko.Spec.KeySpec) {
 		delta.Add("Spec.KeySpec", a.ko.Spec.KeySpec, b.ko.Spec.KeySpec)
 	} else if a.ko.Spec.KeySpec != nil && b.ko.Spec.KeySpec != nil {
@@ -90,6 +141,13 @@ func newResourceDelta(
 			delta.Add("Spec.MultiRegion", a.ko.Spec.MultiRegion, b.ko.Spec.MultiRegion)
 		}
 	}
+	if ackcompare.HasNilDifference(a.ko.Spec.OnDemandRotationGeneration, b.ko.Spec.OnDemandRotationGeneration) {
+		delta.Add("Spec.OnDemandRotationGeneration", a.ko.Spec.OnDemandRotationGeneration, b.ko.Spec.OnDemandRotationGeneration)
+	} else if a.ko.Spec.OnDemandRotationGeneration != nil && b.ko.Spec.OnDemandRotationGeneration != nil {
+		if *a.ko.Spec.OnDemandRotationGeneration != *b.ko.Spec.OnDemandRotationGeneration {
+			delta.Add("Spec.OnDemandRotationGeneration", a.ko.Spec.OnDemandRotationGeneration, b.ko.Spec.OnDemandRotationGeneration)
+		}
+	}
 	if ackcompare.HasNilDifference(a.ko.Spec.Origin, b.ko.Spec.Origin) {
 		delta.Add("Spec.Origin", a.ko.Spec.Origin, b.ko.Spec.Origin)
 	} else if a.ko.Spec.Origin != nil && b.ko.Spec.Origin != nil {
@@ -97,6 +155,13 @@ func newResourceDelta(
 			delta.Add("Spec.Origin", a.ko.Spec.Origin, b.ko.Spec.Origin)
 		}
 	}
+	if ackcompare.HasNilDifference(a.ko.Spec.PendingWindowInDays, b.ko.Spec.PendingWindowInDays) {
+		delta.Add("Spec.PendingWindowInDays", a.ko.Spec.PendingWindowInDays, b.ko.Spec.PendingWindowInDays)
+	} else if a.ko.Spec.PendingWindowInDays != nil && b.ko.Spec.PendingWindowInDays != nil {
+		if *a.ko.Spec.PendingWindowInDays != *b.ko.Spec.PendingWindowInDays {
+			delta.Add("Spec.PendingWindowInDays", a.ko.Spec.PendingWindowInDays, b.ko.Spec.PendingWindowInDays)
+		}
+	}
 	if ackcompare.HasNilDifference(a.ko.Spec.Policy, b.ko.Spec.Policy) {
 		delta.Add("Spec.Policy", a.ko.Spec.Policy, b.ko.Spec.Policy)
 	} else if a.ko.Spec.Policy != nil && b.ko.Spec.Policy != nil {
@@ -104,11 +169,60 @@ func newResourceDelta(
 			delta.Add("Spec.Policy", a.ko.Spec.Policy, b.ko.Spec.Policy)
 		}
 	}
+	if len(a.ko.Spec.PolicyRefs) != len(b.ko.Spec.PolicyRefs) {
+		delta.Add("Spec.PolicyRefs", a.ko.Spec.PolicyRefs, b.ko.Spec.PolicyRefs)
+	} else if len(a.ko.Spec.PolicyRefs) > 0 {
+		if !reflect.DeepEqual(a.ko.Spec.PolicyRefs, b.ko.Spec.PolicyRefs) {
+			delta.Add("Spec.PolicyRefs", a.ko.Spec.PolicyRefs, b.ko.Spec.PolicyRefs)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.PrimaryRegion, b.ko.Spec.PrimaryRegion) {
+		delta.Add("Spec.PrimaryRegion", a.ko.Spec.PrimaryRegion, b.ko.Spec.PrimaryRegion)
+	} else if a.ko.Spec.PrimaryRegion != nil && b.ko.Spec.PrimaryRegion != nil {
+		if *a.ko.Spec.PrimaryRegion != *b.ko.Spec.PrimaryRegion {
+			delta.Add("Spec.PrimaryRegion", a.ko.Spec.PrimaryRegion, b.ko.Spec.PrimaryRegion)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays, b.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays) {
+		delta.Add("Spec.ReimportKeyMaterialBeforeExpiryInDays", a.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays, b.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays)
+	} else if a.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays != nil && b.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays != nil {
+		if *a.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays != *b.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays {
+			delta.Add("Spec.ReimportKeyMaterialBeforeExpiryInDays", a.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays, b.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.RotationPeriodInDays, b.ko.Spec.RotationPeriodInDays) {
+		delta.Add("Spec.RotationPeriodInDays", a.ko.Spec.RotationPeriodInDays, b.ko.Spec.RotationPeriodInDays)
+	} else if a.ko.Spec.RotationPeriodInDays != nil && b.ko.Spec.RotationPeriodInDays != nil {
+		if *a.ko.Spec.RotationPeriodInDays != *b.ko.Spec.RotationPeriodInDays {
+			delta.Add("Spec.RotationPeriodInDays", a.ko.Spec.RotationPeriodInDays, b.ko.Spec.RotationPeriodInDays)
+		}
+	}
 	desiredACKTags, _ := convertToOrderedACKTags(a.ko.Spec.Tags)
 	latestACKTags, _ := convertToOrderedACKTags(b.ko.Spec.Tags)
 	if !ackcompare.MapStringStringEqual(desiredACKTags, latestACKTags) {
 		delta.Add("Spec.Tags", a.ko.Spec.Tags, b.ko.Spec.Tags)
 	}
+	if ackcompare.HasNilDifference(a.ko.Spec.WrappingAlgorithm, b.ko.Spec.WrappingAlgorithm) {
+		delta.Add("Spec.WrappingAlgorithm", a.ko.Spec.WrappingAlgorithm, b.ko.Spec.WrappingAlgorithm)
+	} else if a.ko.Spec.WrappingAlgorithm != nil && b.ko.Spec.WrappingAlgorithm != nil {
+		if *a.ko.Spec.WrappingAlgorithm != *b.ko.Spec.WrappingAlgorithm {
+			delta.Add("Spec.WrappingAlgorithm", a.ko.Spec.WrappingAlgorithm, b.ko.Spec.WrappingAlgorithm)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.WrappingKeySpec, b.ko.Spec.WrappingKeySpec) {
+		delta.Add("Spec.WrappingKeySpec", a.ko.Spec.WrappingKeySpec, b.ko.Spec.WrappingKeySpec)
+	} else if a.ko.Spec.WrappingKeySpec != nil && b.ko.Spec.WrappingKeySpec != nil {
+		if *a.ko.Spec.WrappingKeySpec != *b.ko.Spec.WrappingKeySpec {
+			delta.Add("Spec.WrappingKeySpec", a.ko.Spec.WrappingKeySpec, b.ko.Spec.WrappingKeySpec)
+		}
+	}
+	if ackcompare.HasNilDifference(a.ko.Spec.XksKeyID, b.ko.Spec.XksKeyID) {
+		delta.Add("Spec.XksKeyID", a.ko.Spec.XksKeyID, b.ko.Spec.XksKeyID)
+	} else if a.ko.Spec.XksKeyID != nil && b.ko.Spec.XksKeyID != nil {
+		if *a.ko.Spec.XksKeyID != *b.ko.Spec.XksKeyID {
+			delta.Add("Spec.XksKeyID", a.ko.Spec.XksKeyID, b.ko.Spec.XksKeyID)
+		}
+	}
 
 	return delta
 }
@@ -15,43 +15,171 @@ package key
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"strconv"
+	"time"
 
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+	kmsutil "github.com/aws-controllers-k8s/kms-controller/pkg/util"
 )
 
 const (
 	DefaultDeletePendingWindowInDays = int64(7)
+	// MinDeletePendingWindowInDays and MaxDeletePendingWindowInDays bound the
+	// waiting period accepted by ScheduleKeyDeletion
+	MinDeletePendingWindowInDays = int64(7)
+	MaxDeletePendingWindowInDays = int64(30)
+	// MaxRecentRotations is the maximum number of completed key material
+	// rotations listed in Status.RecentRotations
+	MaxRecentRotations = 10
+)
+
+var (
+	// ConditionReasonKeyDeletionCanceled is the reason of the ACK.Advisory
+	// condition recorded when the scheduled deletion of a KMS key is canceled
+	ConditionReasonKeyDeletionCanceled = "KeyDeletionCanceled"
+	// ConditionReasonInvalidDeletePendingWindow is the reason of the
+	// ACK.Advisory condition recorded when the pending window annotation
+	// cannot be used
+	ConditionReasonInvalidDeletePendingWindow = "InvalidDeletePendingWindow"
+
+	// errDeletionProtected is returned when deleting a Key resource whose
+	// KMS key is protected from deletion
+	errDeletionProtected = ackerr.NewTerminalError(errors.New(
+		"deletion protection is enabled, set spec.deletionProtectionEnabled " +
+			"to false to schedule the deletion of the KMS key",
+	))
+	// errPrimaryRegionNotMultiRegion is returned when Spec.PrimaryRegion is
+	// set on a single-Region key
+	errPrimaryRegionNotMultiRegion = ackerr.NewTerminalError(errors.New(
+		"spec.primaryRegion is only valid for multi-Region keys",
+	))
 )
 
 // GetDeletePendingWindowInDays returns the pending window (in days) as
 // determined by the annotation on the object, or the default value otherwise.
+// Spec.PendingWindowInDays is not taken into account.
 func GetDeletePendingWindowInDays(
 	m *metav1.ObjectMeta,
 ) int64 {
-	resAnnotations := m.GetAnnotations()
-	pendingWindow, ok := resAnnotations[svcapitypes.AnnotationDeletePendingWindow]
-	if !ok {
+	pendingWindow, ok, err := DeletePendingWindowFromAnnotation(m)
+	if !ok || err != nil {
 		return DefaultDeletePendingWindowInDays
 	}
+	return pendingWindow
+}
 
-	pendingWindowInt, err := strconv.Atoi(pendingWindow)
+// DeletePendingWindowFromAnnotation returns the pending window (in days) set
+// by the annotation on the object. ok is false when the annotation is not set,
+// and err is non-nil when its value is not a number of days accepted by
+// ScheduleKeyDeletion.
+func DeletePendingWindowFromAnnotation(
+	m *metav1.ObjectMeta,
+) (pendingWindow int64, ok bool, err error) {
+	value, ok := m.GetAnnotations()[svcapitypes.AnnotationDeletePendingWindow]
+	if !ok {
+		return 0, false, nil
+	}
+	pendingWindow, err = strconv.ParseInt(value, 10, 64)
 	if err != nil {
-		return DefaultDeletePendingWindowInDays
+		return 0, true, fmt.Errorf(
+			"annotation %s must be a number of days, got %q",
+			svcapitypes.AnnotationDeletePendingWindow, value,
+		)
 	}
+	if pendingWindow < MinDeletePendingWindowInDays || pendingWindow > MaxDeletePendingWindowInDays {
+		return 0, true, fmt.Errorf(
+			"annotation %s must be between %d and %d, got %d",
+			svcapitypes.AnnotationDeletePendingWindow,
+			MinDeletePendingWindowInDays, MaxDeletePendingWindowInDays, pendingWindow,
+		)
+	}
+	return pendingWindow, true, nil
+}
 
-	return int64(pendingWindowInt)
+// isDeletionProtected returns true if the KMS key must not be scheduled for
+// deletion when the Key resource is deleted.
+func isDeletionProtected(r *resource) bool {
+	return r.ko.Spec.DeletionProtectionEnabled != nil && *r.ko.Spec.DeletionProtectionEnabled
+}
+
+// getDeletePendingWindowInDays returns the pending window (in days) used when
+// scheduling the deletion of the KMS key. A valid annotation on the object
+// takes precedence over Spec.PendingWindowInDays, which otherwise defaults to
+// DefaultDeletePendingWindowInDays. An invalid annotation is ignored and
+// reported in an ACK.Advisory condition.
+func getDeletePendingWindowInDays(r *resource) int64 {
+	pendingWindow, ok, err := DeletePendingWindowFromAnnotation(&r.ko.ObjectMeta)
+	setDeletePendingWindowAdvisory(r, err)
+	if ok && err == nil {
+		return pendingWindow
+	}
+	if r.ko.Spec.PendingWindowInDays != nil {
+		return *r.ko.Spec.PendingWindowInDays
+	}
+	return DefaultDeletePendingWindowInDays
+}
+
+// validateDeletePendingWindow reports an invalid pending window annotation on
+// the object in an ACK.Advisory condition, so that it is noticed before the
+// Key resource is deleted.
+func validateDeletePendingWindow(r *resource) {
+	_, _, err := DeletePendingWindowFromAnnotation(&r.ko.ObjectMeta)
+	setDeletePendingWindowAdvisory(r, err)
+}
+
+// setDeletePendingWindowAdvisory sets the ACK.Advisory condition describing
+// an invalid pending window annotation, or removes it when err is nil.
+func setDeletePendingWindowAdvisory(r *resource, err error) {
+	if err != nil {
+		msg := err.Error() + ", the annotation is ignored"
+		ackcondition.SetAdvisory(r, corev1.ConditionTrue, &msg, &ConditionReasonInvalidDeletePendingWindow)
+		return
+	}
+	advisory := ackcondition.AdvisoryWithReason(r, ConditionReasonInvalidDeletePendingWindow)
+	if advisory == nil {
+		return
+	}
+	conditions := []*ackv1alpha1.Condition{}
+	for _, c := range r.Conditions() {
+		if c != advisory {
+			conditions = append(conditions, c)
+		}
+	}
+	r.ReplaceConditions(conditions)
+}
+
+// customPreCompare adds the differences the generated code cannot detect to
+// the delta. A KMS key scheduled for deletion differs from any resource that
+// still wants it, so that customUpdate cancels its deletion.
+func customPreCompare(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	comparePolicyDocument(delta, a, b)
+	if isKeyPendingDeletion(b.ko) {
+		delta.Add("Spec.Enabled", a.ko.Spec.Enabled, b.ko.Spec.Enabled)
+	}
 }
 
 // customUpdate is the implementation of update operation for KMS Key resource.
-// This operation will return a Terminal error if the update is not for 'Policy'
-// , 'Tags' or 'BypassPolicyLockoutSafetyCheck' because KMS Key only supports
-// updating those three fields.
+// Only 'Description', 'Policy', 'PolicyDocument', 'Tags',
+// 'EnableKeyRotation', 'RotationPeriodInDays', 'OnDemandRotationGeneration'
+// and 'Enabled' are reconciled; every other field is either immutable or only used on creation.
 func (rm *resourceManager) customUpdate(
 	ctx context.Context,
 	desired *resource,
@@ -63,14 +191,67 @@ func (rm *resourceManager) customUpdate(
 	defer func() {
 		exit(err)
 	}()
+	defer requeueOnThrottling(&err)
+	// A key scheduled for deletion rejects updates, so its deletion is
+	// canceled first and the resources are compared again. CancelKeyDeletion
+	// leaves the key disabled, which a resource that does not set
+	// Spec.Enabled does not ask for.
+	if isKeyPendingDeletion(latest.ko) {
+		latest = rm.concreteResource(latest.DeepCopy())
+		if err = rm.cancelKeyDeletion(ctx, latest); err != nil {
+			return latest, err
+		}
+		if desired.ko.Spec.Enabled == nil {
+			desired = rm.concreteResource(desired.DeepCopy())
+			desired.ko.Spec.Enabled = aws.Bool(true)
+		}
+		delta = newResourceDelta(desired, latest)
+	}
+	// A key that is still being created or otherwise in flux rejects updates,
+	// so wait for it to settle.
+	if isKeyStateTransitional(latest.ko) {
+		return latest, requeueWaitWhileKeyTransitional(latest.ko)
+	}
+
 	updatedRes := rm.concreteResource(desired.DeepCopy())
 	updatedRes.SetStatus(latest)
 
-	if delta.DifferentAt("Spec.Policy") {
-		if updatedRes.ko.Spec.Policy != nil && *updatedRes.ko.Spec.Policy != "" {
+	// A key waiting for its key material can neither be enabled, disabled
+	// nor rotated. Importing the key material enables it, so Spec.Enabled is
+	// reconciled afterwards, and its rotation waits for the import.
+	pendingImport := isKeyPendingImport(latest.ko)
+	rotationChanged := delta.DifferentAt("Spec.EnableKeyRotation") ||
+		delta.DifferentAt("Spec.RotationPeriodInDays") ||
+		delta.DifferentAt("Spec.OnDemandRotationGeneration")
+
+	// A disabled key rejects most other updates (e.g. EnableKeyRotation), so
+	// enable it before anything else and disable it only once everything
+	// else has been reconciled.
+	keyEnabledChanged := delta.DifferentAt("Spec.Enabled") && !pendingImport
+	if keyEnabledChanged && isKeyEnabled(updatedRes) {
+		if err = rm.updateKeyEnabled(ctx, updatedRes); err != nil {
+			return updatedRes, err
+		}
+	}
+	if delta.DifferentAt("Spec.Description") {
+		if updatedRes.ko.Spec.Description != nil {
+			if err = rm.updateDescription(ctx, updatedRes); err != nil {
+				return updatedRes, err
+			}
+		}
+	}
+	if delta.DifferentAt("Spec.Policy") || delta.DifferentAt("Spec.PolicyDocument") ||
+		delta.DifferentAt("Spec.PolicyRefs") {
+		if policyDriftMode(updatedRes.ko) == PolicyDriftModeAdopt {
+			// The key policy of the KMS key becomes the desired one
+			updatedRes.ko.Spec.Policy = latest.ko.Spec.Policy
+			updatedRes.ko.Spec.PolicyDocument = latest.ko.Spec.PolicyDocument.DeepCopy()
+		} else if hasDesiredPolicy(updatedRes.ko) {
 			if err = rm.updatePolicy(ctx, updatedRes); err != nil {
 				return updatedRes, err
 			}
+		} else if err = rm.restoreDefaultPolicy(ctx, updatedRes, latest); err != nil {
+			return updatedRes, err
 		}
 	}
 	if delta.DifferentAt("Spec.Tags") {
@@ -79,16 +260,59 @@ func (rm *resourceManager) customUpdate(
 			return updatedRes, err
 		}
 	}
-	if delta.DifferentAt("Spec.EnableKeyRotation") {
+	if !pendingImport && (delta.DifferentAt("Spec.EnableKeyRotation") ||
+		delta.DifferentAt("Spec.RotationPeriodInDays")) {
 		err = rm.updateKeyRotation(ctx, updatedRes)
 		if err != nil {
 			return updatedRes, err
 		}
 	}
+	if !pendingImport && delta.DifferentAt("Spec.OnDemandRotationGeneration") {
+		err = rm.updateOnDemandRotation(ctx, updatedRes)
+		if err != nil {
+			return updatedRes, err
+		}
+	}
+	if keyEnabledChanged && !isKeyEnabled(updatedRes) {
+		if err = rm.updateKeyEnabled(ctx, updatedRes); err != nil {
+			return updatedRes, err
+		}
+	}
+	// The key is Updating once its primary Region changes, so this comes
+	// last.
+	if delta.DifferentAt("Spec.PrimaryRegion") {
+		if updatedRes.ko.Spec.PrimaryRegion != nil {
+			if err = rm.updatePrimaryRegion(ctx, updatedRes); err != nil {
+				return updatedRes, err
+			}
+		}
+	}
+	if pendingImport && rotationChanged {
+		return updatedRes, requeueWaitForKeyMaterial(latest.ko)
+	}
 	rm.setStatusDefaults(updatedRes.ko)
+	setKeyUsableCondition(updatedRes.ko)
 	return updatedRes, nil
 }
 
+// updateDescription performs the UpdateKeyDescription API call using the
+// Spec.Description field of the resource in the parameter
+func (rm *resourceManager) updateDescription(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateDescription")
+	defer func() {
+		exit(err)
+	}()
+
+	input := &svcsdk.UpdateKeyDescriptionInput{
+		KeyId:       r.ko.Status.KeyID,
+		Description: r.ko.Spec.Description,
+	}
+	_, err = rm.sdkapi.UpdateKeyDescription(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "UpdateKeyDescription", err)
+	return err
+}
+
 func (rm *resourceManager) updateKeyRotation(ctx context.Context, r *resource) (err error) {
 	rlog := ackrtlog.FromContext(ctx)
 	exit := rlog.Trace("rm.updateKeyRotation")
@@ -107,7 +331,7 @@ func (rm *resourceManager) updateKeyRotation(ctx context.Context, r *resource) (
 		return nil
 	}
 
-	if r.ko.Spec.EnableKeyRotation == nil {
+	if r.ko.Spec.EnableKeyRotation == nil || !*r.ko.Spec.EnableKeyRotation {
 		// check if current status of key is enabled
 		if keyRotationStatus.KeyRotationEnabled {
 			return rm.disableKeyRotation(ctx, r.ko.Status.KeyID)
@@ -115,20 +339,146 @@ func (rm *resourceManager) updateKeyRotation(ctx context.Context, r *resource) (
 		return nil
 	}
 
-	// Check if desired state and actual state has any difference
-	if keyRotationStatus.KeyRotationEnabled != *r.ko.Spec.EnableKeyRotation {
-		switch *r.ko.Spec.EnableKeyRotation {
-		case true:
-			return rm.enableKeyRotation(ctx, r.ko.Status.KeyID)
-		case false:
-			return rm.disableKeyRotation(ctx, r.ko.Status.KeyID)
+	// EnableKeyRotation is also used to change the rotation period of a key
+	// that already has automatic rotation enabled
+	if !keyRotationStatus.KeyRotationEnabled ||
+		rotationPeriodChanged(r.ko.Spec.RotationPeriodInDays, keyRotationStatus.RotationPeriodInDays) {
+		return rm.enableKeyRotation(ctx, r.ko.Status.KeyID, r.ko.Spec.RotationPeriodInDays)
+	}
+
+	return nil
+}
+
+// rotationPeriodChanged returns true if a rotation period is desired and it
+// differs from the rotation period currently configured on the kms key
+func rotationPeriodChanged(desired *int64, latest *int32) bool {
+	if desired == nil {
+		return false
+	}
+	return latest == nil || int64(*latest) != *desired
+}
+
+// setKeyRotationStatus copies the automatic rotation settings and dates
+// returned by GetKeyRotationStatus into the supplied Key
+func setKeyRotationStatus(
+	ko *svcapitypes.Key,
+	keyRotationStatus *svcsdk.GetKeyRotationStatusOutput,
+) {
+	enabled := keyRotationStatus.KeyRotationEnabled
+	ko.Spec.EnableKeyRotation = &enabled
+	// The rotation period is only reported while automatic rotation is
+	// enabled, keep the desired value otherwise. A key that does not set it
+	// rotates every 365 days, which is not a difference either.
+	if keyRotationStatus.RotationPeriodInDays != nil && ko.Spec.RotationPeriodInDays != nil {
+		rotationPeriodInDays := int64(*keyRotationStatus.RotationPeriodInDays)
+		ko.Spec.RotationPeriodInDays = &rotationPeriodInDays
+	}
+	if keyRotationStatus.NextRotationDate != nil {
+		ko.Status.NextRotationDate = &metav1.Time{Time: *keyRotationStatus.NextRotationDate}
+	} else {
+		ko.Status.NextRotationDate = nil
+	}
+	if keyRotationStatus.OnDemandRotationStartDate != nil {
+		ko.Status.OnDemandRotationStartDate = &metav1.Time{Time: *keyRotationStatus.OnDemandRotationStartDate}
+	} else {
+		ko.Status.OnDemandRotationStartDate = nil
+	}
+}
+
+// listRecentRotations performs the ListKeyRotations API call, following
+// pagination, and returns at most MaxRecentRotations completed rotations of
+// the key material, newest first. Keys whose key material cannot be rotated
+// have no rotations.
+func (rm *resourceManager) listRecentRotations(ctx context.Context, r *resource) (rotations []*svcapitypes.RotationsListEntry, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.listRecentRotations")
+	defer func() {
+		exit(err)
+	}()
+	var truncated = true
+	var marker *string
+	entries := []svcsdktypes.RotationsListEntry{}
+	for truncated {
+		listKeyRotationsInput := svcsdk.ListKeyRotationsInput{
+			KeyId:  r.ko.Status.KeyID,
+			Marker: marker,
+		}
+		resp, err := rm.sdkapi.ListKeyRotations(ctx, &listKeyRotationsInput)
+		rm.metrics.RecordAPICall("GET", "ListKeyRotations", err)
+		if err != nil {
+			if awsErr, ok := ackerr.AWSError(err); ok && awsErr.ErrorCode() == "UnsupportedOperationException" {
+				return nil, nil
+			}
+			return nil, err
+		}
+		entries = append(entries, resp.Rotations...)
+		truncated = resp.Truncated
+		marker = resp.NextMarker
+	}
+	return newestRotations(entries, MaxRecentRotations), nil
+}
+
+// newestRotations returns the max most recent entries, newest first
+func newestRotations(entries []svcsdktypes.RotationsListEntry, max int) []*svcapitypes.RotationsListEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].RotationDate == nil || entries[j].RotationDate == nil {
+			return entries[j].RotationDate == nil && entries[i].RotationDate != nil
+		}
+		return entries[i].RotationDate.After(*entries[j].RotationDate)
+	})
+	if len(entries) > max {
+		entries = entries[:max]
+	}
+	rotations := make([]*svcapitypes.RotationsListEntry, 0, len(entries))
+	for _, entry := range entries {
+		rotation := &svcapitypes.RotationsListEntry{
+			KeyID: entry.KeyId,
+		}
+		if entry.RotationDate != nil {
+			rotation.RotationDate = &metav1.Time{Time: *entry.RotationDate}
+		}
+		if entry.RotationType != "" {
+			rotation.RotationType = aws.String(string(entry.RotationType))
 		}
+		rotations = append(rotations, rotation)
 	}
+	return rotations
+}
+
+// updateOnDemandRotation performs the RotateKeyOnDemand API call once for
+// every new value of Spec.OnDemandRotationGeneration. The last generation the
+// key material was rotated for is recorded in
+// Status.LastOnDemandRotationGeneration.
+func (rm *resourceManager) updateOnDemandRotation(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateOnDemandRotation")
+	defer func() {
+		exit(err)
+	}()
 
+	if r.ko.Spec.OnDemandRotationGeneration == nil {
+		r.ko.Status.LastOnDemandRotationGeneration = nil
+		return nil
+	}
+	input := &svcsdk.RotateKeyOnDemandInput{
+		KeyId: r.ko.Status.KeyID,
+	}
+	_, err = rm.sdkapi.RotateKeyOnDemand(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "RotateKeyOnDemand", err)
+	if err != nil {
+		return err
+	}
+	generation := *r.ko.Spec.OnDemandRotationGeneration
+	r.ko.Status.LastOnDemandRotationGeneration = &generation
 	return nil
 }
 
-// get key rotation status at the kms key
+// get key rotation status at the kms key. Keys that do not support automatic
+// rotation, such as asymmetric keys or keys with imported key material, have
+// no rotation status.
 func (rm *resourceManager) getKeyRotationStatus(ctx context.Context, r *resource) (*svcsdk.GetKeyRotationStatusOutput, error) {
 	keyRotationInput := svcsdk.GetKeyRotationStatusInput{
 		KeyId: r.ko.Status.KeyID,
@@ -136,17 +486,24 @@ func (rm *resourceManager) getKeyRotationStatus(ctx context.Context, r *resource
 	resp, err := rm.sdkapi.GetKeyRotationStatus(ctx, &keyRotationInput)
 	rm.metrics.RecordAPICall("GET", "GetKeyRotationStatus", err)
 	if err != nil {
+		if awsErr, ok := ackerr.AWSError(err); ok && awsErr.ErrorCode() == "UnsupportedOperationException" {
+			return nil, nil
+		}
 		return nil, err
 	}
 
 	return resp, nil
 }
 
-// enable key rotation on the kms key
-func (rm *resourceManager) enableKeyRotation(ctx context.Context, keyId *string) error {
+// enable key rotation on the kms key, using the KMS default rotation period
+// when rotationPeriodInDays is nil
+func (rm *resourceManager) enableKeyRotation(ctx context.Context, keyId *string, rotationPeriodInDays *int64) error {
 	enableKeyRotationInput := svcsdk.EnableKeyRotationInput{
 		KeyId: keyId,
 	}
+	if rotationPeriodInDays != nil {
+		enableKeyRotationInput.RotationPeriodInDays = aws.Int32(int32(*rotationPeriodInDays))
+	}
 	_, err := rm.sdkapi.EnableKeyRotation(ctx, &enableKeyRotationInput)
 	rm.metrics.RecordAPICall("UPDATE", "EnableKeyRotation", err)
 	if err != nil {
@@ -167,3 +524,183 @@ func (rm *resourceManager) disableKeyRotation(ctx context.Context, keyId *string
 	}
 	return nil
 }
+
+// isKeyEnabled returns true unless the resource explicitly asks for the KMS
+// key to be disabled.
+func isKeyEnabled(r *resource) bool {
+	return r.ko.Spec.Enabled == nil || *r.ko.Spec.Enabled
+}
+
+// updateKeyEnabled calls EnableKey or DisableKey so that the state of the KMS
+// key matches Spec.Enabled. Nothing is done when Spec.Enabled is not set.
+func (rm *resourceManager) updateKeyEnabled(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateKeyEnabled")
+	defer func() {
+		exit(err)
+	}()
+
+	if r.ko.Spec.Enabled == nil {
+		return nil
+	}
+	if *r.ko.Spec.Enabled {
+		err = rm.enableKey(ctx, r.ko.Status.KeyID)
+	} else {
+		err = rm.disableKey(ctx, r.ko.Status.KeyID)
+	}
+	if err != nil {
+		return err
+	}
+	enabled := *r.ko.Spec.Enabled
+	keyState := string(svcsdktypes.KeyStateDisabled)
+	if enabled {
+		keyState = string(svcsdktypes.KeyStateEnabled)
+	}
+	r.ko.Status.Enabled = &enabled
+	r.ko.Status.KeyState = &keyState
+	return nil
+}
+
+// enable the kms key
+func (rm *resourceManager) enableKey(ctx context.Context, keyId *string) error {
+	enableKeyInput := svcsdk.EnableKeyInput{
+		KeyId: keyId,
+	}
+	_, err := rm.sdkapi.EnableKey(ctx, &enableKeyInput)
+	rm.metrics.RecordAPICall("UPDATE", "EnableKey", err)
+	return err
+}
+
+// disable the kms key
+func (rm *resourceManager) disableKey(ctx context.Context, keyId *string) error {
+	disableKeyInput := svcsdk.DisableKeyInput{
+		KeyId: keyId,
+	}
+	_, err := rm.sdkapi.DisableKey(ctx, &disableKeyInput)
+	rm.metrics.RecordAPICall("UPDATE", "DisableKey", err)
+	return err
+}
+
+// primaryKeyRegion returns the Region of the primary key of a multi-Region
+// key, or nil for a single-Region key.
+func primaryKeyRegion(ko *svcapitypes.Key) *string {
+	mrc := ko.Status.MultiRegionConfiguration
+	if mrc == nil || mrc.PrimaryKey == nil {
+		return nil
+	}
+	return mrc.PrimaryKey.Region
+}
+
+// updatePrimaryRegion promotes the replica key in Spec.PrimaryRegion to
+// primary key. UpdatePrimaryRegion is only accepted in the Region of the
+// current primary key, which is not necessarily the Region of the resource.
+// The key is Updating until KMS has moved the primary key.
+func (rm *resourceManager) updatePrimaryRegion(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updatePrimaryRegion")
+	defer func() {
+		exit(err)
+	}()
+	currentRegion := primaryKeyRegion(r.ko)
+	if currentRegion == nil {
+		return errPrimaryRegionNotMultiRegion
+	}
+	input := &svcsdk.UpdatePrimaryRegionInput{
+		KeyId:         r.ko.Status.KeyID,
+		PrimaryRegion: r.ko.Spec.PrimaryRegion,
+	}
+	_, err = rm.sdkapi.UpdatePrimaryRegion(ctx, input, func(o *svcsdk.Options) {
+		o.Region = *currentRegion
+	})
+	rm.metrics.RecordAPICall("UPDATE", "UpdatePrimaryRegion", err)
+	if err != nil {
+		return err
+	}
+	keyState := string(svcsdktypes.KeyStateUpdating)
+	r.ko.Status.KeyState = &keyState
+	return nil
+}
+
+// isKeyPendingDeletion returns true if the KMS key is scheduled for deletion.
+func isKeyPendingDeletion(ko *svcapitypes.Key) bool {
+	if ko.Status.KeyState == nil {
+		return false
+	}
+	switch svcsdktypes.KeyState(*ko.Status.KeyState) {
+	case svcsdktypes.KeyStatePendingDeletion,
+		svcsdktypes.KeyStatePendingReplicaDeletion:
+		return true
+	}
+	return false
+}
+
+// cancelKeyDeletion cancels the scheduled deletion of a KMS key that is still
+// wanted by a Key resource, e.g. after a Key was deleted by mistake and then
+// re-created or adopted, and reads the state of the key again. The
+// cancellation is recorded in an ACK.Advisory condition.
+func (rm *resourceManager) cancelKeyDeletion(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.cancelKeyDeletion")
+	defer func() {
+		exit(err)
+	}()
+
+	input := &svcsdk.CancelKeyDeletionInput{
+		KeyId: r.ko.Status.KeyID,
+	}
+	_, err = rm.sdkapi.CancelKeyDeletion(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "CancelKeyDeletion", err)
+	if err != nil {
+		return err
+	}
+
+	msg := "Scheduled deletion of the KMS key was canceled"
+	if r.ko.Status.DeletionDate != nil {
+		msg = fmt.Sprintf(
+			"Scheduled deletion of the KMS key on %s was canceled",
+			r.ko.Status.DeletionDate.UTC().Format(time.RFC3339),
+		)
+	}
+	r.ko.Status.DeletionDate = nil
+	ackcondition.SetAdvisory(r, corev1.ConditionTrue, &msg, &ConditionReasonKeyDeletionCanceled)
+
+	resp, err := rm.sdkapi.DescribeKey(ctx, &svcsdk.DescribeKeyInput{KeyId: r.ko.Status.KeyID})
+	rm.metrics.RecordAPICall("READ_ONE", "DescribeKey", err)
+	if err != nil {
+		return err
+	}
+	keyState := string(resp.KeyMetadata.KeyState)
+	enabled := resp.KeyMetadata.Enabled
+	r.ko.Status.KeyState = &keyState
+	r.ko.Status.Enabled = &enabled
+	if !isKeyPendingImport(r.ko) {
+		r.ko.Spec.Enabled = aws.Bool(enabled)
+	}
+	return nil
+}
+
+// setKeyDeletionStatus sets the Status fields of the resource from the
+// output of ScheduleKeyDeletion, so that the deletion date of the KMS key
+// stays visible while the resource is being deleted.
+func setKeyDeletionStatus(
+	ko *svcapitypes.Key,
+	out *svcsdk.ScheduleKeyDeletionOutput,
+) {
+	if out.DeletionDate != nil {
+		ko.Status.DeletionDate = &metav1.Time{Time: *out.DeletionDate}
+	}
+	if out.KeyState != "" {
+		keyState := string(out.KeyState)
+		ko.Status.KeyState = &keyState
+	}
+	if out.PendingWindowInDays != nil {
+		ko.Status.PendingDeletionWindowInDays = aws.Int64(int64(*out.PendingWindowInDays))
+	}
+}
+
+// requeueOnThrottling turns the KMS throttling error err points to into a
+// requeue error, see kmsutil.RequeueOnThrottling. The operations of the
+// resource manager defer it.
+func requeueOnThrottling(err *error) {
+	*err = kmsutil.RequeueOnThrottling(*err)
+}
@@ -0,0 +1,329 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+func describeKeyOutput(state svcsdktypes.KeyState) *svcsdk.DescribeKeyOutput {
+	deletionDate := time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC)
+	return &svcsdk.DescribeKeyOutput{
+		KeyMetadata: &svcsdktypes.KeyMetadata{
+			KeyId:        aws.String("1234abcd-12ab-34cd-56ef-1234567890ab"),
+			Arn:          aws.String("arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+			KeyState:     state,
+			Enabled:      false,
+			DeletionDate: &deletionDate,
+		},
+	}
+}
+
+func TestCustomUpdate_CancelKeyDeletion(t *testing.T) {
+	tests := []struct {
+		name            string
+		state           svcsdktypes.KeyState
+		enabled         *bool
+		enableErr       error
+		expectedCalls   []string
+		expectedState   string
+		expectedEnabled bool
+	}{
+		{
+			name:            "pending deletion key is restored",
+			state:           svcsdktypes.KeyStatePendingDeletion,
+			expectedCalls:   []string{"CancelKeyDeletion", "DescribeKey", "EnableKey"},
+			expectedState:   string(svcsdktypes.KeyStateEnabled),
+			expectedEnabled: true,
+		},
+		{
+			name:            "pending replica deletion key is restored",
+			state:           svcsdktypes.KeyStatePendingReplicaDeletion,
+			enabled:         aws.Bool(true),
+			expectedCalls:   []string{"CancelKeyDeletion", "DescribeKey", "EnableKey"},
+			expectedState:   string(svcsdktypes.KeyStateEnabled),
+			expectedEnabled: true,
+		},
+		{
+			name:            "restored key stays disabled",
+			state:           svcsdktypes.KeyStatePendingDeletion,
+			enabled:         aws.Bool(false),
+			expectedCalls:   []string{"CancelKeyDeletion", "DescribeKey"},
+			expectedState:   string(svcsdktypes.KeyStateDisabled),
+			expectedEnabled: false,
+		},
+		{
+			name:            "restored key fails to be enabled",
+			state:           svcsdktypes.KeyStatePendingDeletion,
+			enabled:         aws.Bool(true),
+			enableErr:       &smithy.GenericAPIError{Code: "DependencyTimeoutException"},
+			expectedCalls:   []string{"CancelKeyDeletion", "DescribeKey", "EnableKey"},
+			expectedState:   string(svcsdktypes.KeyStateDisabled),
+			expectedEnabled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(nil)
+			desired.ko.Spec.Enabled = tt.enabled
+			desired.ko.Spec.EnableKeyRotation = aws.Bool(false)
+
+			fake := newFakeSDKAPI()
+			fake.Outputs["DescribeKey"] = describeKeyOutput(tt.state)
+			rm := newFakeResourceManager(fake)
+			latest, err := rm.sdkFind(context.TODO(), desired)
+			require.NoError(t, err)
+			// Reading the key leaves its scheduled deletion alone
+			assert.False(t, fake.Called("CancelKeyDeletion"))
+			assert.Equal(t, string(tt.state), *latest.ko.Status.KeyState)
+
+			delta := newResourceDelta(desired, latest)
+			require.True(t, delta.DifferentAt("Spec"))
+
+			// CancelKeyDeletion leaves the key disabled
+			fake.Calls = nil
+			fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateDisabled)
+			if tt.enableErr != nil {
+				fake.Errors["EnableKey"] = tt.enableErr
+			}
+			updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+			assert.Equal(t, tt.expectedCalls, fake.Calls)
+			if tt.enableErr != nil {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedState, *updated.ko.Status.KeyState)
+			assert.Equal(t, tt.expectedEnabled, *updated.ko.Status.Enabled)
+			assert.Equal(t, tt.enabled, desired.ko.Spec.Enabled)
+
+			advisory := ackcondition.AdvisoryWithReason(updated, ConditionReasonKeyDeletionCanceled)
+			require.NotNil(t, advisory)
+			assert.Equal(t, ackv1alpha1.ConditionTypeAdvisory, advisory.Type)
+			assert.Equal(t, corev1.ConditionTrue, advisory.Status)
+			assert.Contains(t, *advisory.Message, "2026-10-24T00:00:00Z")
+			assert.Nil(t, updated.ko.Status.DeletionDate)
+		})
+	}
+
+	t.Run("disabled key is left alone", func(t *testing.T) {
+		desired := newTestKey(nil)
+		desired.ko.Spec.Enabled = aws.Bool(false)
+
+		fake := newFakeSDKAPI()
+		fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateDisabled)
+		rm := newFakeResourceManager(fake)
+		latest, err := rm.sdkFind(context.TODO(), desired)
+		require.NoError(t, err)
+		assert.False(t, newResourceDelta(desired, latest).DifferentAt("Spec.Enabled"))
+	})
+}
+
+func TestSdkFind_BeingDeleted(t *testing.T) {
+	tests := []struct {
+		name           string
+		state          svcsdktypes.KeyState
+		expectNotFound bool
+	}{
+		{
+			name:           "pending deletion key is gone",
+			state:          svcsdktypes.KeyStatePendingDeletion,
+			expectNotFound: true,
+		},
+		{
+			name:           "pending replica deletion key is gone",
+			state:          svcsdktypes.KeyStatePendingReplicaDeletion,
+			expectNotFound: true,
+		},
+		{
+			name:           "enabled key still exists",
+			state:          svcsdktypes.KeyStateEnabled,
+			expectNotFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(nil)
+			now := metav1.Now()
+			desired.ko.DeletionTimestamp = &now
+
+			fake := newFakeSDKAPI()
+			fake.Outputs["DescribeKey"] = describeKeyOutput(tt.state)
+			rm := newFakeResourceManager(fake)
+			latest, err := rm.sdkFind(context.TODO(), desired)
+
+			assert.False(t, fake.Called("CancelKeyDeletion"))
+			assert.False(t, fake.Called("EnableKey"))
+			if !tt.expectNotFound {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, ackerr.NotFound, err)
+			require.NotNil(t, latest)
+			require.NotNil(t, latest.ko.Status.DeletionDate)
+			assert.Equal(t, time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC), latest.ko.Status.DeletionDate.Time)
+			// No more calls after the key was found scheduled for deletion
+			assert.Equal(t, []string{"DescribeKey"}, fake.Calls)
+
+			_, err = rm.ReadOne(context.TODO(), desired)
+			assert.Equal(t, ackerr.NotFound, err)
+		})
+	}
+}
+
+func TestDelete_SchedulesKeyDeletion(t *testing.T) {
+	desired := newTestKey(nil)
+	now := metav1.Now()
+	desired.ko.DeletionTimestamp = &now
+	desired.ko.Annotations = map[string]string{
+		svcapitypes.AnnotationDeletePendingWindow: "10",
+	}
+
+	deletionDate := time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC)
+	fake := newFakeSDKAPI()
+	fake.Outputs["ScheduleKeyDeletion"] = &svcsdk.ScheduleKeyDeletionOutput{
+		KeyId:               desired.ko.Status.KeyID,
+		DeletionDate:        &deletionDate,
+		KeyState:            svcsdktypes.KeyStatePendingDeletion,
+		PendingWindowInDays: aws.Int32(10),
+	}
+	rm := newFakeResourceManager(fake)
+	res, err := rm.Delete(context.TODO(), desired)
+	require.NoError(t, err)
+
+	input := fake.Inputs["ScheduleKeyDeletion"].(*svcsdk.ScheduleKeyDeletionInput)
+	assert.Equal(t, int32(10), *input.PendingWindowInDays)
+
+	latest := rm.concreteResource(res)
+	require.NotNil(t, latest.ko.Status.DeletionDate)
+	assert.Equal(t, deletionDate, latest.ko.Status.DeletionDate.Time)
+	assert.Equal(t, string(svcsdktypes.KeyStatePendingDeletion), *latest.ko.Status.KeyState)
+	assert.Equal(t, int64(10), *latest.ko.Status.PendingDeletionWindowInDays)
+
+	// The post-delete read no longer finds the key
+	fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStatePendingDeletion)
+	_, err = rm.ReadOne(context.TODO(), latest)
+	assert.Equal(t, ackerr.NotFound, err)
+}
+
+func TestGetDeletePendingWindowInDays(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotation      *string
+		spec            *int64
+		expected        int64
+		expectAdvisory  bool
+		expectedMessage string
+	}{
+		{
+			name:     "default",
+			expected: DefaultDeletePendingWindowInDays,
+		},
+		{
+			name:     "spec",
+			spec:     aws.Int64(20),
+			expected: 20,
+		},
+		{
+			name:       "annotation overrides spec",
+			annotation: aws.String("25"),
+			spec:       aws.Int64(20),
+			expected:   25,
+		},
+		{
+			name:            "unparsable annotation falls back to spec",
+			annotation:      aws.String("not-an-int"),
+			spec:            aws.Int64(20),
+			expected:        20,
+			expectAdvisory:  true,
+			expectedMessage: `annotation kms.services.k8s.aws/pending-window-in-days must be a number of days, got "not-an-int", the annotation is ignored`,
+		},
+		{
+			name:            "out of range annotation falls back to default",
+			annotation:      aws.String("3"),
+			expected:        DefaultDeletePendingWindowInDays,
+			expectAdvisory:  true,
+			expectedMessage: "annotation kms.services.k8s.aws/pending-window-in-days must be between 7 and 30, got 3, the annotation is ignored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestKey(nil)
+			r.ko.Spec.PendingWindowInDays = tt.spec
+			if tt.annotation != nil {
+				r.ko.Annotations = map[string]string{
+					svcapitypes.AnnotationDeletePendingWindow: *tt.annotation,
+				}
+			}
+
+			assert.Equal(t, tt.expected, getDeletePendingWindowInDays(r))
+			advisory := ackcondition.AdvisoryWithReason(r, ConditionReasonInvalidDeletePendingWindow)
+			if !tt.expectAdvisory {
+				assert.Nil(t, advisory)
+				return
+			}
+			require.NotNil(t, advisory)
+			assert.Equal(t, tt.expectedMessage, *advisory.Message)
+
+			// Fixing the annotation clears the advisory
+			r.ko.Annotations[svcapitypes.AnnotationDeletePendingWindow] = "30"
+			validateDeletePendingWindow(r)
+			assert.Nil(t, ackcondition.AdvisoryWithReason(r, ConditionReasonInvalidDeletePendingWindow))
+		})
+	}
+}
+
+func TestDelete_DeletionProtection(t *testing.T) {
+	desired := newTestKey(nil)
+	now := metav1.Now()
+	desired.ko.DeletionTimestamp = &now
+	desired.ko.Spec.DeletionProtectionEnabled = aws.Bool(true)
+
+	fake := newFakeSDKAPI()
+	rm := newFakeResourceManager(fake)
+	res, err := rm.Delete(context.TODO(), desired)
+	assert.Equal(t, ackerr.Terminal, err)
+	assert.Empty(t, fake.Calls)
+
+	protected := rm.concreteResource(res)
+	terminal := ackcondition.Terminal(protected)
+	require.NotNil(t, terminal)
+	assert.Equal(t, corev1.ConditionTrue, terminal.Status)
+	assert.Contains(t, *terminal.Message, "spec.deletionProtectionEnabled")
+
+	// Lifting the protection lets the deletion proceed
+	protected.ko.Spec.DeletionProtectionEnabled = aws.Bool(false)
+	_, err = rm.Delete(context.TODO(), protected)
+	require.NoError(t, err)
+	assert.True(t, fake.Called("ScheduleKeyDeletion"))
+}
@@ -0,0 +1,369 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+// fakeReconciler stores the Secrets of a single namespace in memory, keyed
+// by name.
+type fakeReconciler struct {
+	acktypes.Reconciler
+	secrets map[string]map[string]string
+}
+
+func (r *fakeReconciler) SecretValueFromReference(
+	ctx context.Context,
+	ref *ackv1alpha1.SecretKeyReference,
+) (string, error) {
+	if value, ok := r.secrets[ref.Name][ref.Key]; ok {
+		return value, nil
+	}
+	return "", ackerr.SecretNotFound
+}
+
+func (r *fakeReconciler) WriteToSecret(
+	ctx context.Context,
+	value string,
+	namespace string,
+	name string,
+	key string,
+) error {
+	secret, ok := r.secrets[name]
+	if !ok {
+		return ackerr.SecretNotFound
+	}
+	secret[key] = value
+	return nil
+}
+
+func newTestExternalKey() *resource {
+	r := newTestKey(nil)
+	r.ko.Namespace = "default"
+	r.ko.Spec.Origin = aws.String(string(svcsdktypes.OriginTypeExternal))
+	r.ko.Spec.EncryptedKeyMaterial = &ackv1alpha1.SecretKeyReference{
+		SecretReference: corev1.SecretReference{Name: "key-material"},
+		Key:             "encryptedKeyMaterial",
+	}
+	return r
+}
+
+func getParametersForImportOutput() *svcsdk.GetParametersForImportOutput {
+	validTo := time.Now().Add(24 * time.Hour)
+	return &svcsdk.GetParametersForImportOutput{
+		ImportToken:       []byte("token"),
+		PublicKey:         []byte("public-key"),
+		ParametersValidTo: &validTo,
+	}
+}
+
+func TestSdkCreate_PendingImport(t *testing.T) {
+	desired := newTestExternalKey()
+	desired.ko.Spec.Enabled = aws.Bool(false)
+
+	fake := newFakeSDKAPI()
+	out := describeKeyOutput(svcsdktypes.KeyStatePendingImport)
+	fake.Outputs["CreateKey"] = &svcsdk.CreateKeyOutput{KeyMetadata: out.KeyMetadata}
+	fake.Outputs["GetParametersForImport"] = getParametersForImportOutput()
+	rr := &fakeReconciler{secrets: map[string]map[string]string{"key-material": {}}}
+	rm := newFakeResourceManager(fake)
+	rm.rr = rr
+
+	created, err := rm.sdkCreate(context.TODO(), desired)
+	require.NoError(t, err)
+
+	input := fake.Inputs["GetParametersForImport"].(*svcsdk.GetParametersForImportInput)
+	assert.Equal(t, DefaultWrappingAlgorithm, input.WrappingAlgorithm)
+	assert.Equal(t, DefaultWrappingKeySpec, input.WrappingKeySpec)
+	assert.Equal(t, []byte("token"), created.ko.Status.ImportToken)
+	assert.Equal(t, []byte("public-key"), created.ko.Status.PublicKey)
+	assert.NotNil(t, created.ko.Status.ImportParametersValidTo)
+	assert.Equal(t, map[string]string{
+		ImportParametersPublicKeySecretKey: "public-key",
+		ImportParametersTokenSecretKey:     "token",
+	}, rr.secrets["key-material"])
+	// The key material is not in the Secret yet
+	assert.False(t, fake.Called("ImportKeyMaterial"))
+	// A key waiting for its key material cannot be disabled
+	assert.False(t, fake.Called("DisableKey"))
+}
+
+func TestSdkFind_ImportKeyMaterial(t *testing.T) {
+	validTo := metav1.NewTime(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name            string
+		material        *string
+		importErr       error
+		expectImport    bool
+		expectImported  bool
+		expectParamsSet bool
+	}{
+		{
+			name:            "key material not provided yet",
+			expectParamsSet: true,
+		},
+		{
+			name:           "key material imported",
+			material:       aws.String("wrapped"),
+			expectImport:   true,
+			expectImported: true,
+		},
+		{
+			name:         "expired import token",
+			material:     aws.String("wrapped"),
+			importErr:    &smithy.GenericAPIError{Code: "ExpiredImportTokenException"},
+			expectImport: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestExternalKey()
+			desired.ko.Spec.ImportExpirationModel = aws.String(string(svcsdktypes.ExpirationModelTypeKeyMaterialExpires))
+			desired.ko.Spec.ImportValidTo = &validTo
+			params := getParametersForImportOutput()
+			desired.ko.Status.ImportToken = params.ImportToken
+			desired.ko.Status.PublicKey = params.PublicKey
+			desired.ko.Status.ImportParametersValidTo = &metav1.Time{Time: *params.ParametersValidTo}
+
+			fake := newFakeSDKAPI()
+			fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStatePendingImport)
+			if tt.importErr != nil {
+				fake.Errors["ImportKeyMaterial"] = tt.importErr
+			}
+			secret := map[string]string{}
+			if tt.material != nil {
+				secret["encryptedKeyMaterial"] = *tt.material
+			}
+			rm := newFakeResourceManager(fake)
+			rm.rr = &fakeReconciler{secrets: map[string]map[string]string{"key-material": secret}}
+
+			latest, err := rm.sdkFind(context.TODO(), desired)
+			if tt.importErr != nil {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// The parameters in the status are still valid
+			assert.False(t, fake.Called("GetParametersForImport"))
+			assert.Equal(t, tt.expectImport, fake.Called("ImportKeyMaterial"))
+			if tt.expectImport {
+				input := fake.Inputs["ImportKeyMaterial"].(*svcsdk.ImportKeyMaterialInput)
+				assert.Equal(t, []byte("wrapped"), input.EncryptedKeyMaterial)
+				assert.Equal(t, []byte("token"), input.ImportToken)
+				assert.Equal(t, svcsdktypes.ExpirationModelTypeKeyMaterialExpires, input.ExpirationModel)
+				assert.Equal(t, validTo.Time, *input.ValidTo)
+			}
+			assert.Equal(t, tt.expectParamsSet, latest.ko.Status.ImportToken != nil)
+			if tt.expectImported {
+				assert.Equal(t, string(svcsdktypes.KeyStateEnabled), *latest.ko.Status.KeyState)
+				assert.True(t, *latest.ko.Status.Enabled)
+				assert.Equal(t, &validTo, latest.ko.Status.ValidTo)
+				assert.Equal(t, aws.Bool(true), latest.ko.Spec.Enabled)
+			} else {
+				assert.Equal(t, string(svcsdktypes.KeyStatePendingImport), *latest.ko.Status.KeyState)
+			}
+		})
+	}
+}
+
+func TestSdkFind_PendingImportEnabled(t *testing.T) {
+	desired := newTestExternalKey()
+	desired.ko.Spec.EncryptedKeyMaterial = nil
+
+	fake := newFakeSDKAPI()
+	fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStatePendingImport)
+	fake.Outputs["GetParametersForImport"] = getParametersForImportOutput()
+	rm := newFakeResourceManager(fake)
+
+	latest, err := rm.sdkFind(context.TODO(), desired)
+	require.NoError(t, err)
+
+	assert.True(t, fake.Called("GetParametersForImport"))
+	assert.Equal(t, []byte("public-key"), latest.ko.Status.PublicKey)
+	// The key is enabled once its key material is imported, its disabled
+	// state until then is not late initialized into Spec.Enabled
+	assert.Equal(t, aws.Bool(true), latest.ko.Spec.Enabled)
+	assert.False(t, *latest.ko.Status.Enabled)
+}
+
+func TestSetKeyMaterialExpiringCondition(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		expiresIn      time.Duration
+		warningDays    []*int64
+		expectedReason *string
+	}{
+		{
+			name:      "key material far from expiring",
+			expiresIn: 45 * 24 * time.Hour,
+		},
+		{
+			name:           "default thresholds",
+			expiresIn:      5 * 24 * time.Hour,
+			expectedReason: aws.String("ExpiresWithin7Days"),
+		},
+		{
+			name:           "custom thresholds",
+			expiresIn:      10 * 24 * time.Hour,
+			warningDays:    []*int64{aws.Int64(60), aws.Int64(14)},
+			expectedReason: aws.String("ExpiresWithin14Days"),
+		},
+		{
+			name:        "no thresholds",
+			expiresIn:   12 * time.Hour,
+			warningDays: []*int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestExternalKey()
+			r.ko.Spec.KeyMaterialExpiryWarningDays = tt.warningDays
+			r.ko.Status.ExpirationModel = aws.String(string(svcsdktypes.ExpirationModelTypeKeyMaterialExpires))
+			r.ko.Status.ValidTo = &metav1.Time{Time: now.Add(tt.expiresIn)}
+
+			setKeyMaterialExpiringCondition(r.ko, now)
+
+			c := ackcondition.FirstOfType(r, svcapitypes.ConditionTypeKeyMaterialExpiring)
+			if tt.expectedReason == nil {
+				assert.Nil(t, c)
+				return
+			}
+			require.NotNil(t, c)
+			assert.Equal(t, corev1.ConditionTrue, c.Status)
+			assert.Equal(t, tt.expectedReason, c.Reason)
+		})
+	}
+}
+
+func TestSdkFind_ReimportKeyMaterial(t *testing.T) {
+	tests := []struct {
+		name             string
+		importErr        error
+		validityInDays   *int64
+		expectImport     bool
+		expectReimported bool
+	}{
+		{
+			name:             "key material re-imported",
+			validityInDays:   aws.Int64(90),
+			expectImport:     true,
+			expectReimported: true,
+		},
+		{
+			name:           "key material not wrapped with the new public key yet",
+			validityInDays: aws.Int64(90),
+			importErr:      &smithy.GenericAPIError{Code: "InvalidCiphertextException"},
+			expectImport:   true,
+		},
+		{
+			name: "no later expiration date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validTo := time.Now().Add(3 * 24 * time.Hour)
+			desired := newTestExternalKey()
+			desired.ko.Spec.ReimportKeyMaterialBeforeExpiryInDays = aws.Int64(7)
+			desired.ko.Spec.ImportValidityInDays = tt.validityInDays
+
+			fake := newFakeSDKAPI()
+			out := describeKeyOutput(svcsdktypes.KeyStateEnabled)
+			out.KeyMetadata.Enabled = true
+			out.KeyMetadata.ExpirationModel = svcsdktypes.ExpirationModelTypeKeyMaterialExpires
+			out.KeyMetadata.ValidTo = &validTo
+			fake.Outputs["DescribeKey"] = out
+			fake.Outputs["GetParametersForImport"] = getParametersForImportOutput()
+			if tt.importErr != nil {
+				fake.Errors["ImportKeyMaterial"] = tt.importErr
+			}
+			rr := &fakeReconciler{secrets: map[string]map[string]string{
+				"key-material": {"encryptedKeyMaterial": "wrapped"},
+			}}
+			rm := newFakeResourceManager(fake)
+			rm.rr = rr
+
+			latest, err := rm.sdkFind(context.TODO(), desired)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectImport, fake.Called("GetParametersForImport"))
+			assert.Equal(t, tt.expectImport, fake.Called("ImportKeyMaterial"))
+			assert.Equal(t, string(svcsdktypes.KeyStateEnabled), *latest.ko.Status.KeyState)
+			c := ackcondition.FirstOfType(latest, svcapitypes.ConditionTypeKeyMaterialExpiring)
+			if tt.expectReimported {
+				assert.True(t, latest.ko.Status.ValidTo.After(validTo.Add(80*24*time.Hour)))
+				assert.Nil(t, latest.ko.Status.ImportToken)
+				assert.Nil(t, c)
+				return
+			}
+			assert.True(t, latest.ko.Status.ValidTo.Equal(&metav1.Time{Time: validTo}))
+			require.NotNil(t, c)
+			assert.Equal(t, "ExpiresWithin7Days", *c.Reason)
+			if tt.expectImport {
+				// New import parameters wait for the key material to be
+				// wrapped again
+				assert.Equal(t, "token", rr.secrets["key-material"][ImportParametersTokenSecretKey])
+				assert.NotNil(t, latest.ko.Status.ImportToken)
+			}
+		})
+	}
+}
+
+func TestSdkFind_KeyMaterialExpiryWithoutRotationStatus(t *testing.T) {
+	// Imported keys have no rotation status, their key material expiry is
+	// still tracked
+	validTo := time.Now().Add(5 * 24 * time.Hour)
+	desired := newTestExternalKey()
+	desired.ko.Spec.Enabled = aws.Bool(true)
+
+	fake := newFakeSDKAPI()
+	out := describeKeyOutput(svcsdktypes.KeyStateEnabled)
+	out.KeyMetadata.Enabled = true
+	out.KeyMetadata.Origin = svcsdktypes.OriginTypeExternal
+	out.KeyMetadata.ExpirationModel = svcsdktypes.ExpirationModelTypeKeyMaterialExpires
+	out.KeyMetadata.ValidTo = &validTo
+	fake.Outputs["DescribeKey"] = out
+	fake.Errors["GetKeyRotationStatus"] = &smithy.GenericAPIError{Code: "UnsupportedOperationException"}
+	rm := newFakeResourceManager(fake)
+
+	latest, err := rm.sdkFind(context.TODO(), desired)
+	require.NoError(t, err)
+	assert.True(t, fake.Called("ListKeyRotations"))
+	assert.Nil(t, latest.ko.Spec.EnableKeyRotation)
+	c := ackcondition.FirstOfType(latest, svcapitypes.ConditionTypeKeyMaterialExpiring)
+	require.NotNil(t, c)
+	assert.Equal(t, "ExpiresWithin7Days", *c.Reason)
+}
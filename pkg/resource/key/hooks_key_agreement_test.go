@@ -0,0 +1,50 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSdkFind_KeyAgreementAlgorithms(t *testing.T) {
+	out := describeKeyOutput(svcsdktypes.KeyStateEnabled)
+	out.KeyMetadata.Enabled = true
+	out.KeyMetadata.DeletionDate = nil
+	out.KeyMetadata.KeySpec = svcsdktypes.KeySpecEccNistP256
+	out.KeyMetadata.KeyUsage = svcsdktypes.KeyUsageTypeKeyAgreement
+	out.KeyMetadata.KeyAgreementAlgorithms = []svcsdktypes.KeyAgreementAlgorithmSpec{
+		svcsdktypes.KeyAgreementAlgorithmSpecEcdh,
+	}
+
+	fake := newFakeSDKAPI()
+	fake.Outputs["DescribeKey"] = out
+	rm := newFakeResourceManager(fake)
+
+	latest, err := rm.sdkFind(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	assert.Equal(t, []*string{aws.String("ECDH")}, latest.ko.Status.KeyAgreementAlgorithms)
+	assert.Equal(t, aws.String("KEY_AGREEMENT"), latest.ko.Spec.KeyUsage)
+
+	// Keys for any other usage have no key agreement algorithms
+	fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateEnabled)
+	latest, err = rm.sdkFind(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	assert.Nil(t, latest.ko.Status.KeyAgreementAlgorithms)
+}
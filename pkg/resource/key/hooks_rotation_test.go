@@ -0,0 +1,138 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewestRotations(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	entries := []svcsdktypes.RotationsListEntry{
+		{KeyId: aws.String("k"), RotationDate: day(1), RotationType: svcsdktypes.RotationTypeAutomatic},
+		{KeyId: aws.String("k"), RotationDate: day(3), RotationType: svcsdktypes.RotationTypeOnDemand},
+		{KeyId: aws.String("k"), RotationDate: nil},
+		{KeyId: aws.String("k"), RotationDate: day(2), RotationType: svcsdktypes.RotationTypeAutomatic},
+	}
+
+	rotations := newestRotations(entries, 2)
+	require.Len(t, rotations, 2)
+	assert.Equal(t, *day(3), rotations[0].RotationDate.Time)
+	assert.Equal(t, "ON_DEMAND", *rotations[0].RotationType)
+	assert.Equal(t, *day(2), rotations[1].RotationDate.Time)
+	assert.Equal(t, "AUTOMATIC", *rotations[1].RotationType)
+
+	rotations = newestRotations(entries, MaxRecentRotations)
+	require.Len(t, rotations, 4)
+	assert.Nil(t, rotations[3].RotationDate)
+
+	assert.Nil(t, newestRotations(nil, MaxRecentRotations))
+}
+
+func TestListRecentRotations(t *testing.T) {
+	rotationDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	fake := newFakeSDKAPI()
+	fake.Outputs["ListKeyRotations"] = &svcsdk.ListKeyRotationsOutput{
+		Rotations: []svcsdktypes.RotationsListEntry{
+			{KeyId: aws.String("k"), RotationDate: &rotationDate, RotationType: svcsdktypes.RotationTypeAutomatic},
+		},
+	}
+	rm := newFakeResourceManager(fake)
+	rotations, err := rm.listRecentRotations(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	require.Len(t, rotations, 1)
+	assert.Equal(t, rotationDate, rotations[0].RotationDate.Time)
+
+	// Keys whose key material cannot be rotated have no rotation history
+	fake = newFakeSDKAPI()
+	fake.Errors["ListKeyRotations"] = &smithy.GenericAPIError{Code: "UnsupportedOperationException"}
+	rm = newFakeResourceManager(fake)
+	rotations, err = rm.listRecentRotations(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	assert.Nil(t, rotations)
+}
+
+func TestGetKeyRotationStatus_Unsupported(t *testing.T) {
+	// Asymmetric, HMAC and imported keys have no rotation status
+	fake := newFakeSDKAPI()
+	fake.Errors["GetKeyRotationStatus"] = &smithy.GenericAPIError{Code: "UnsupportedOperationException"}
+	rm := newFakeResourceManager(fake)
+	status, err := rm.getKeyRotationStatus(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	fake = newFakeSDKAPI()
+	fake.Errors["GetKeyRotationStatus"] = &smithy.GenericAPIError{Code: "KMSInvalidStateException"}
+	rm = newFakeResourceManager(fake)
+	_, err = rm.getKeyRotationStatus(context.TODO(), newTestKey(nil))
+	assert.Error(t, err)
+}
+
+func TestSetKeyRotationStatus_RotationPeriod(t *testing.T) {
+	tests := []struct {
+		name           string
+		desired        *int64
+		observed       *int32
+		expectedPeriod *int64
+	}{
+		{
+			name:           "default period not set in the spec",
+			observed:       aws.Int32(365),
+			expectedPeriod: nil,
+		},
+		{
+			name:           "period drifted",
+			desired:        aws.Int64(90),
+			observed:       aws.Int32(180),
+			expectedPeriod: aws.Int64(180),
+		},
+		{
+			name:           "rotation disabled",
+			desired:        aws.Int64(90),
+			expectedPeriod: aws.Int64(90),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest := newTestKey(nil)
+			latest.ko.Spec.RotationPeriodInDays = tt.desired
+			setKeyRotationStatus(latest.ko, &svcsdk.GetKeyRotationStatusOutput{
+				KeyRotationEnabled:   tt.observed != nil,
+				RotationPeriodInDays: tt.observed,
+			})
+			assert.Equal(t, tt.expectedPeriod, latest.ko.Spec.RotationPeriodInDays)
+
+			desired := newTestKey(nil)
+			desired.ko.Spec.EnableKeyRotation = latest.ko.Spec.EnableKeyRotation
+			desired.ko.Spec.RotationPeriodInDays = tt.desired
+			delta := newResourceDelta(desired, latest)
+			assert.Equal(t, tt.desired != nil && tt.observed != nil && int64(*tt.observed) != *tt.desired,
+				delta.DifferentAt("Spec.RotationPeriodInDays"))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+func TestKeyState(t *testing.T) {
+	tests := []struct {
+		state           svcsdktypes.KeyState
+		expectSynced    bool
+		expectUsable    corev1.ConditionStatus
+		expectRequeue   bool
+		expectedMessage string
+	}{
+		{
+			state:        svcsdktypes.KeyStateEnabled,
+			expectSynced: true,
+			expectUsable: corev1.ConditionTrue,
+		},
+		{
+			state:           svcsdktypes.KeyStateDisabled,
+			expectSynced:    true,
+			expectUsable:    corev1.ConditionFalse,
+			expectedMessage: "The KMS key is disabled",
+		},
+		{
+			state:           svcsdktypes.KeyStateCreating,
+			expectUsable:    corev1.ConditionFalse,
+			expectRequeue:   true,
+			expectedMessage: "The KMS key is being created in its custom key store",
+		},
+		{
+			state:           svcsdktypes.KeyStatePendingImport,
+			expectUsable:    corev1.ConditionFalse,
+			expectedMessage: "The KMS key is waiting for its key material to be imported",
+		},
+		{
+			// The scheduled deletion of the key is canceled before it is
+			// updated
+			state:           svcsdktypes.KeyStatePendingReplicaDeletion,
+			expectUsable:    corev1.ConditionFalse,
+			expectedMessage: "The KMS key is waiting for its replica keys to be deleted",
+		},
+		{
+			state:           svcsdktypes.KeyStateUnavailable,
+			expectUsable:    corev1.ConditionFalse,
+			expectRequeue:   true,
+			expectedMessage: "The custom key store of the KMS key is disconnected",
+		},
+		{
+			state:           svcsdktypes.KeyStateUpdating,
+			expectUsable:    corev1.ConditionFalse,
+			expectRequeue:   true,
+			expectedMessage: "The KMS key is being updated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			latest := newTestKey(aws.String("old description"))
+			latest.ko.Status.KeyState = aws.String(string(tt.state))
+			desired := newTestKey(aws.String("new description"))
+
+			fake := newFakeSDKAPI()
+			fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateDisabled)
+			rm := newFakeResourceManager(fake)
+
+			synced, err := rm.IsSynced(context.TODO(), latest)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectSynced, synced)
+
+			setKeyUsableCondition(latest.ko)
+			usable := ackcondition.FirstOfType(latest, svcapitypes.ConditionTypeKeyUsable)
+			require.NotNil(t, usable)
+			assert.Equal(t, tt.expectUsable, usable.Status)
+			assert.Equal(t, string(tt.state), *usable.Reason)
+			if tt.expectedMessage == "" {
+				assert.Nil(t, usable.Message)
+			} else {
+				assert.Equal(t, tt.expectedMessage, *usable.Message)
+			}
+
+			delta := newResourceDelta(desired, latest)
+			_, err = rm.customUpdate(context.TODO(), desired, latest, delta)
+			var requeueNeeded *ackrequeue.RequeueNeeded
+			assert.Equal(t, tt.expectRequeue, errors.As(err, &requeueNeeded))
+			assert.Equal(t, !tt.expectRequeue, fake.Called("UpdateKeyDescription"))
+		})
+	}
+}
+
+func TestCustomUpdate_PendingImport(t *testing.T) {
+	tests := []struct {
+		name          string
+		update        func(ko *svcapitypes.Key)
+		expectRequeue bool
+		expectCalled  []string
+		expectSkipped []string
+	}{
+		{
+			name: "description and tags are updated",
+			update: func(ko *svcapitypes.Key) {
+				ko.Spec.Description = aws.String("new description")
+				ko.Spec.Tags = []*svcapitypes.Tag{{TagKey: aws.String("k"), TagValue: aws.String("v")}}
+			},
+			expectCalled: []string{"UpdateKeyDescription", "TagResource"},
+		},
+		{
+			name: "enabled waits for the import",
+			update: func(ko *svcapitypes.Key) {
+				ko.Spec.Enabled = aws.Bool(false)
+			},
+			expectSkipped: []string{"EnableKey", "DisableKey"},
+		},
+		{
+			name: "rotation waits for the import",
+			update: func(ko *svcapitypes.Key) {
+				ko.Spec.Description = aws.String("new description")
+				ko.Spec.EnableKeyRotation = aws.Bool(true)
+			},
+			expectRequeue: true,
+			expectCalled:  []string{"UpdateKeyDescription"},
+			expectSkipped: []string{"GetKeyRotationStatus", "EnableKeyRotation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest := newTestKey(aws.String("old description"))
+			latest.ko.Status.KeyState = aws.String(string(svcsdktypes.KeyStatePendingImport))
+			latest.ko.Spec.Enabled = aws.Bool(true)
+			desired := latest.DeepCopy().(*resource)
+			tt.update(desired.ko)
+
+			fake := newFakeSDKAPI()
+			rm := newFakeResourceManager(fake)
+
+			delta := newResourceDelta(desired, latest)
+			_, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+			if tt.expectRequeue {
+				var requeueNeeded *ackrequeue.RequeueNeeded
+				assert.True(t, errors.As(err, &requeueNeeded))
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, op := range tt.expectCalled {
+				assert.True(t, fake.Called(op), op)
+			}
+			for _, op := range tt.expectSkipped {
+				assert.False(t, fake.Called(op), op)
+			}
+		})
+	}
+}
+
+func TestIsSynced_NoKeyState(t *testing.T) {
+	rm := newFakeResourceManager(newFakeSDKAPI())
+	synced, err := rm.IsSynced(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	assert.False(t, synced)
+}
+
+func TestReadOne_Throttling(t *testing.T) {
+	fake := newFakeSDKAPI()
+	fake.Errors["DescribeKey"] = &smithy.GenericAPIError{Code: "LimitExceededException"}
+	rm := newFakeResourceManager(fake)
+
+	latest, err := rm.ReadOne(context.TODO(), newTestKey(nil))
+	var requeueNeededAfter *ackrequeue.RequeueNeededAfter
+	require.True(t, errors.As(err, &requeueNeededAfter))
+	recoverable := ackcondition.Recoverable(latest)
+	require.NotNil(t, recoverable)
+	assert.Contains(t, *recoverable.Message, "LimitExceededException")
+}
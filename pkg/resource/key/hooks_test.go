@@ -33,6 +33,11 @@ func Test_GetDeletePendingWindowInDays(t *testing.T) {
 			svcapitypes.AnnotationDeletePendingWindow: "not-an-int",
 		},
 	}
+	outOfRangeAnnotation := metav1.ObjectMeta{
+		Annotations: map[string]string{
+			svcapitypes.AnnotationDeletePendingWindow: "31",
+		},
+	}
 	validAnnotation := metav1.ObjectMeta{
 		Annotations: map[string]string{
 			svcapitypes.AnnotationDeletePendingWindow: "25",
@@ -41,5 +46,6 @@ func Test_GetDeletePendingWindowInDays(t *testing.T) {
 
 	assert.Equal(key.GetDeletePendingWindowInDays(&noAnnotation), key.DefaultDeletePendingWindowInDays)
 	assert.Equal(key.GetDeletePendingWindowInDays(&badAnnotation), key.DefaultDeletePendingWindowInDays)
+	assert.Equal(key.GetDeletePendingWindowInDays(&outOfRangeAnnotation), key.DefaultDeletePendingWindowInDays)
 	assert.Equal(key.GetDeletePendingWindowInDays(&validAnnotation), int64(25))
 }
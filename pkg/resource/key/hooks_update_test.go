@@ -0,0 +1,306 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+func newTestKey(description *string) *resource {
+	return &resource{
+		ko: &svcapitypes.Key{
+			Spec: svcapitypes.KeySpec{
+				Description: description,
+			},
+			Status: svcapitypes.KeyStatus{
+				KeyID: aws.String("1234abcd-12ab-34cd-56ef-1234567890ab"),
+			},
+		},
+	}
+}
+
+func TestCustomUpdate_Description(t *testing.T) {
+	tests := []struct {
+		name               string
+		desired            *string
+		latest             *string
+		expectDelta        bool
+		expectUpdateCalled bool
+	}{
+		{
+			name:               "description changed",
+			desired:            aws.String("new description"),
+			latest:             aws.String("old description"),
+			expectDelta:        true,
+			expectUpdateCalled: true,
+		},
+		{
+			name:               "description cleared explicitly",
+			desired:            aws.String(""),
+			latest:             aws.String("old description"),
+			expectDelta:        true,
+			expectUpdateCalled: true,
+		},
+		{
+			name:               "description set on key without one",
+			desired:            aws.String("new description"),
+			latest:             aws.String(""),
+			expectDelta:        true,
+			expectUpdateCalled: true,
+		},
+		{
+			name:               "description not managed",
+			desired:            nil,
+			latest:             aws.String("old description"),
+			expectDelta:        true,
+			expectUpdateCalled: false,
+		},
+		{
+			name:               "description unchanged",
+			desired:            aws.String("same description"),
+			latest:             aws.String("same description"),
+			expectDelta:        false,
+			expectUpdateCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(tt.desired)
+			latest := newTestKey(tt.latest)
+
+			delta := newResourceDelta(desired, latest)
+			assert.Equal(t, tt.expectDelta, delta.DifferentAt("Spec.Description"))
+
+			fake := newFakeSDKAPI()
+			rm := newFakeResourceManager(fake)
+			updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+			require.NoError(t, err)
+			assert.Equal(t, tt.desired, updated.ko.Spec.Description)
+
+			assert.Equal(t, tt.expectUpdateCalled, fake.Called("UpdateKeyDescription"))
+			if tt.expectUpdateCalled {
+				input := fake.Inputs["UpdateKeyDescription"].(*svcsdk.UpdateKeyDescriptionInput)
+				assert.Equal(t, *tt.desired, *input.Description)
+				assert.Equal(t, *latest.ko.Status.KeyID, *input.KeyId)
+			}
+		})
+	}
+}
+
+func TestCustomUpdate_RotationPeriod(t *testing.T) {
+	tests := []struct {
+		name           string
+		desired        *int64
+		latest         *int32
+		expectEnable   bool
+		expectedPeriod *int32
+	}{
+		{
+			name:           "rotation period changed",
+			desired:        aws.Int64(180),
+			latest:         aws.Int32(365),
+			expectEnable:   true,
+			expectedPeriod: aws.Int32(180),
+		},
+		{
+			name:         "rotation period unchanged",
+			desired:      aws.Int64(365),
+			latest:       aws.Int32(365),
+			expectEnable: false,
+		},
+		{
+			name:         "rotation period not managed",
+			desired:      nil,
+			latest:       aws.Int32(180),
+			expectEnable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(nil)
+			desired.ko.Spec.EnableKeyRotation = aws.Bool(true)
+			desired.ko.Spec.RotationPeriodInDays = tt.desired
+			latest := newTestKey(nil)
+			latest.ko.Spec.EnableKeyRotation = aws.Bool(true)
+			if tt.latest != nil {
+				latest.ko.Spec.RotationPeriodInDays = aws.Int64(int64(*tt.latest))
+			}
+
+			fake := newFakeSDKAPI()
+			fake.Outputs["GetKeyRotationStatus"] = &svcsdk.GetKeyRotationStatusOutput{
+				KeyRotationEnabled:   true,
+				RotationPeriodInDays: tt.latest,
+			}
+			rm := newFakeResourceManager(fake)
+			delta := newResourceDelta(desired, latest)
+			_, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectEnable, fake.Called("EnableKeyRotation"))
+			assert.False(t, fake.Called("DisableKeyRotation"))
+			if tt.expectEnable {
+				input := fake.Inputs["EnableKeyRotation"].(*svcsdk.EnableKeyRotationInput)
+				assert.Equal(t, tt.expectedPeriod, input.RotationPeriodInDays)
+			}
+		})
+	}
+}
+
+func TestCustomUpdate_OnDemandRotation(t *testing.T) {
+	tests := []struct {
+		name             string
+		desired          *int64
+		lastRotated      *int64
+		expectRotate     bool
+		expectedRecorded *int64
+	}{
+		{
+			name:             "first on-demand rotation",
+			desired:          aws.Int64(1),
+			lastRotated:      nil,
+			expectRotate:     true,
+			expectedRecorded: aws.Int64(1),
+		},
+		{
+			name:             "generation bumped",
+			desired:          aws.Int64(2),
+			lastRotated:      aws.Int64(1),
+			expectRotate:     true,
+			expectedRecorded: aws.Int64(2),
+		},
+		{
+			name:             "generation unchanged",
+			desired:          aws.Int64(2),
+			lastRotated:      aws.Int64(2),
+			expectRotate:     false,
+			expectedRecorded: aws.Int64(2),
+		},
+		{
+			name:             "generation removed",
+			desired:          nil,
+			lastRotated:      aws.Int64(2),
+			expectRotate:     false,
+			expectedRecorded: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(nil)
+			desired.ko.Spec.OnDemandRotationGeneration = tt.desired
+			latest := newTestKey(nil)
+			latest.ko.Status.LastOnDemandRotationGeneration = tt.lastRotated
+			latest.ko.Spec.OnDemandRotationGeneration = tt.lastRotated
+
+			fake := newFakeSDKAPI()
+			rm := newFakeResourceManager(fake)
+			delta := newResourceDelta(desired, latest)
+			updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectRotate, fake.Called("RotateKeyOnDemand"))
+			assert.Equal(t, tt.expectedRecorded, updated.ko.Status.LastOnDemandRotationGeneration)
+		})
+	}
+}
+
+func TestCustomUpdate_PrimaryRegion(t *testing.T) {
+	tests := []struct {
+		name           string
+		desired        *string
+		latest         *string
+		multiRegion    bool
+		expectUpdate   bool
+		expectTerminal bool
+	}{
+		{
+			name:         "replica promoted to primary",
+			desired:      aws.String("us-east-1"),
+			latest:       aws.String("us-west-2"),
+			multiRegion:  true,
+			expectUpdate: true,
+		},
+		{
+			name:        "primary region unchanged",
+			desired:     aws.String("us-west-2"),
+			latest:      aws.String("us-west-2"),
+			multiRegion: true,
+		},
+		{
+			name:        "primary region not managed",
+			desired:     nil,
+			latest:      aws.String("us-west-2"),
+			multiRegion: true,
+		},
+		{
+			name:           "single-Region key",
+			desired:        aws.String("us-east-1"),
+			latest:         nil,
+			expectTerminal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(nil)
+			desired.ko.Spec.PrimaryRegion = tt.desired
+			latest := newTestKey(nil)
+			latest.ko.Spec.PrimaryRegion = tt.latest
+			latest.ko.Status.KeyState = aws.String(string(svcsdktypes.KeyStateEnabled))
+			if tt.multiRegion {
+				latest.ko.Status.MultiRegionConfiguration = &svcapitypes.MultiRegionConfiguration{
+					PrimaryKey: &svcapitypes.MultiRegionKey{
+						Region: aws.String("us-west-2"),
+					},
+				}
+			}
+
+			delta := newResourceDelta(desired, latest)
+			fake := newFakeSDKAPI()
+			rm := newFakeResourceManager(fake)
+			// The resource is managed in the Region of the replica key
+			rm.awsRegion = "eu-west-1"
+			updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+
+			if tt.expectTerminal {
+				var termErr *ackerr.TerminalError
+				assert.True(t, errors.As(err, &termErr))
+				assert.False(t, fake.Called("UpdatePrimaryRegion"))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectUpdate, fake.Called("UpdatePrimaryRegion"))
+			if tt.expectUpdate {
+				input := fake.Inputs["UpdatePrimaryRegion"].(*svcsdk.UpdatePrimaryRegionInput)
+				assert.Equal(t, *tt.desired, *input.PrimaryRegion)
+				assert.Equal(t, *latest.ko.Status.KeyID, *input.KeyId)
+				assert.Equal(t, "us-west-2", fake.Regions["UpdatePrimaryRegion"])
+				assert.Equal(t, string(svcsdktypes.KeyStateUpdating), *updated.ko.Status.KeyState)
+			}
+		})
+	}
+}
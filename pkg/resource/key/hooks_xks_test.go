@@ -0,0 +1,79 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func xksKeyMetadata(state svcsdktypes.KeyState) *svcsdktypes.KeyMetadata {
+	md := describeKeyOutput(state).KeyMetadata
+	md.Enabled = true
+	md.DeletionDate = nil
+	md.Origin = svcsdktypes.OriginTypeExternalKeyStore
+	md.CustomKeyStoreId = aws.String("cks-1234567890abcdef0")
+	md.XksKeyConfiguration = &svcsdktypes.XksKeyConfigurationType{
+		Id: aws.String("xks-key-1"),
+	}
+	return md
+}
+
+func TestSdkCreate_XksKey(t *testing.T) {
+	desired := newTestKey(nil)
+	desired.ko.Spec.Origin = aws.String(string(svcsdktypes.OriginTypeExternalKeyStore))
+	desired.ko.Spec.CustomKeyStoreID = aws.String("cks-1234567890abcdef0")
+	desired.ko.Spec.XksKeyID = aws.String("xks-key-1")
+
+	fake := newFakeSDKAPI()
+	fake.Outputs["CreateKey"] = &svcsdk.CreateKeyOutput{
+		KeyMetadata: xksKeyMetadata(svcsdktypes.KeyStateEnabled),
+	}
+	rm := newFakeResourceManager(fake)
+
+	created, err := rm.sdkCreate(context.TODO(), desired)
+	require.NoError(t, err)
+
+	input := fake.Inputs["CreateKey"].(*svcsdk.CreateKeyInput)
+	assert.Equal(t, aws.String("xks-key-1"), input.XksKeyId)
+	assert.Equal(t, aws.String("cks-1234567890abcdef0"), input.CustomKeyStoreId)
+	assert.Equal(t, svcsdktypes.OriginTypeExternalKeyStore, input.Origin)
+	require.NotNil(t, created.ko.Status.XksKeyConfiguration)
+	assert.Equal(t, aws.String("xks-key-1"), created.ko.Status.XksKeyConfiguration.ID)
+}
+
+func TestSdkFind_XksKeyConfiguration(t *testing.T) {
+	fake := newFakeSDKAPI()
+	fake.Outputs["DescribeKey"] = &svcsdk.DescribeKeyOutput{
+		KeyMetadata: xksKeyMetadata(svcsdktypes.KeyStateEnabled),
+	}
+	rm := newFakeResourceManager(fake)
+
+	latest, err := rm.sdkFind(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	require.NotNil(t, latest.ko.Status.XksKeyConfiguration)
+	assert.Equal(t, aws.String("xks-key-1"), latest.ko.Status.XksKeyConfiguration.ID)
+
+	// A key outside of an external key store has no XKS key
+	fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateEnabled)
+	latest, err = rm.sdkFind(context.TODO(), newTestKey(nil))
+	require.NoError(t, err)
+	assert.Nil(t, latest.ko.Status.XksKeyConfiguration)
+}
@@ -0,0 +1,373 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	smithy "github.com/aws/smithy-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+const (
+	// DefaultWrappingAlgorithm and DefaultWrappingKeySpec are used to request
+	// import parameters when Spec.WrappingAlgorithm and Spec.WrappingKeySpec
+	// are not set
+	DefaultWrappingAlgorithm = svcsdktypes.AlgorithmSpecRsaesOaepSha256
+	DefaultWrappingKeySpec   = svcsdktypes.WrappingKeySpecRsa4096
+
+	// ImportParametersPublicKeySecretKey and ImportParametersTokenSecretKey
+	// are the keys of the Secret referenced by Spec.EncryptedKeyMaterial that
+	// the public key and the import token are written to
+	ImportParametersPublicKeySecretKey = "publicKey"
+	ImportParametersTokenSecretKey     = "importToken"
+)
+
+var (
+	// DefaultKeyMaterialExpiryWarningDays are the thresholds, in days before
+	// the imported key material expires, of the KMS.KeyMaterialExpiring
+	// condition when Spec.KeyMaterialExpiryWarningDays is not set
+	DefaultKeyMaterialExpiryWarningDays = []int64{30, 7, 1}
+)
+
+// isKeyPendingImport returns true if the KMS key is waiting for its key
+// material to be imported.
+func isKeyPendingImport(ko *svcapitypes.Key) bool {
+	return ko.Status.KeyState != nil &&
+		*ko.Status.KeyState == string(svcsdktypes.KeyStatePendingImport)
+}
+
+// importParametersExpired returns true if there are no import parameters in
+// the status of the resource, or if they cannot be used anymore.
+func importParametersExpired(ko *svcapitypes.Key) bool {
+	return ko.Status.ImportToken == nil ||
+		ko.Status.PublicKey == nil ||
+		ko.Status.ImportParametersValidTo == nil ||
+		!time.Now().Before(ko.Status.ImportParametersValidTo.Time)
+}
+
+// syncKeyMaterialImport drives the import of key material into a KMS key
+// that is PendingImport. It publishes the public key and import token of the
+// key in its status and, once Spec.EncryptedKeyMaterial references key
+// material wrapped with that public key, imports it.
+func (rm *resourceManager) syncKeyMaterialImport(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.syncKeyMaterialImport")
+	defer func() {
+		exit(err)
+	}()
+	if importParametersExpired(r.ko) {
+		if err = rm.getParametersForImport(ctx, r); err != nil {
+			return err
+		}
+		if err = rm.publishImportParameters(ctx, r); err != nil {
+			// The parameters are requested and published again on the next
+			// reconciliation
+			clearImportParameters(r.ko)
+			return ackrequeue.Needed(err)
+		}
+	}
+	if r.ko.Spec.EncryptedKeyMaterial == nil {
+		return nil
+	}
+	return rm.importKeyMaterial(ctx, r, importValidTo(r.ko, time.Now()))
+}
+
+// publishImportParameters writes the public key and import token in the
+// status of the resource to the Secret referenced by Spec.EncryptedKeyMaterial.
+func (rm *resourceManager) publishImportParameters(ctx context.Context, r *resource) error {
+	ref := r.ko.Spec.EncryptedKeyMaterial
+	if ref == nil {
+		return nil
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = r.ko.Namespace
+	}
+	err := rm.rr.WriteToSecret(ctx, string(r.ko.Status.PublicKey), namespace, ref.Name, ImportParametersPublicKeySecretKey)
+	if err != nil {
+		return err
+	}
+	return rm.rr.WriteToSecret(ctx, string(r.ko.Status.ImportToken), namespace, ref.Name, ImportParametersTokenSecretKey)
+}
+
+// getParametersForImport performs the GetParametersForImport API call and
+// stores the public key and import token in the status of the resource.
+func (rm *resourceManager) getParametersForImport(ctx context.Context, r *resource) (err error) {
+	input := &svcsdk.GetParametersForImportInput{
+		KeyId:             r.ko.Status.KeyID,
+		WrappingAlgorithm: DefaultWrappingAlgorithm,
+		WrappingKeySpec:   DefaultWrappingKeySpec,
+	}
+	if r.ko.Spec.WrappingAlgorithm != nil {
+		input.WrappingAlgorithm = svcsdktypes.AlgorithmSpec(*r.ko.Spec.WrappingAlgorithm)
+	}
+	if r.ko.Spec.WrappingKeySpec != nil {
+		input.WrappingKeySpec = svcsdktypes.WrappingKeySpec(*r.ko.Spec.WrappingKeySpec)
+	}
+	resp, err := rm.sdkapi.GetParametersForImport(ctx, input)
+	rm.metrics.RecordAPICall("GET", "GetParametersForImport", err)
+	if err != nil {
+		return err
+	}
+	r.ko.Status.ImportToken = resp.ImportToken
+	r.ko.Status.PublicKey = resp.PublicKey
+	r.ko.Status.ImportParametersValidTo = nil
+	if resp.ParametersValidTo != nil {
+		r.ko.Status.ImportParametersValidTo = &metav1.Time{Time: *resp.ParametersValidTo}
+	}
+	return nil
+}
+
+// importKeyMaterial performs the ImportKeyMaterial API call with the wrapped
+// key material read from the Secret referenced by Spec.EncryptedKeyMaterial,
+// which expires at validTo. The import token is single-use, so it is cleared
+// from the status along with the public key once the key material is imported.
+func (rm *resourceManager) importKeyMaterial(
+	ctx context.Context,
+	r *resource,
+	validTo *metav1.Time,
+) (err error) {
+	encryptedKeyMaterial, err := rm.rr.SecretValueFromReference(ctx, r.ko.Spec.EncryptedKeyMaterial)
+	if err == ackerr.SecretNotFound || (err == nil && encryptedKeyMaterial == "") {
+		// The key material has not been wrapped with the public key yet
+		return nil
+	}
+	if err != nil {
+		return ackrequeue.Needed(err)
+	}
+	input := &svcsdk.ImportKeyMaterialInput{
+		KeyId:                r.ko.Status.KeyID,
+		EncryptedKeyMaterial: []byte(encryptedKeyMaterial),
+		ImportToken:          r.ko.Status.ImportToken,
+	}
+	if r.ko.Spec.ImportExpirationModel != nil {
+		input.ExpirationModel = svcsdktypes.ExpirationModelType(*r.ko.Spec.ImportExpirationModel)
+	}
+	if validTo != nil {
+		input.ValidTo = &validTo.Time
+	}
+	_, err = rm.sdkapi.ImportKeyMaterial(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "ImportKeyMaterial", err)
+	if err != nil {
+		var awsErr smithy.APIError
+		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ExpiredImportTokenException" {
+			// New import parameters are requested on the next reconciliation
+			clearImportParameters(r.ko)
+		}
+		return err
+	}
+	// Importing key material enables a key that was waiting for it, a
+	// re-import leaves the key state alone
+	if isKeyPendingImport(r.ko) {
+		enabled := true
+		keyState := string(svcsdktypes.KeyStateEnabled)
+		r.ko.Status.Enabled = &enabled
+		r.ko.Status.KeyState = &keyState
+	}
+	r.ko.Status.ExpirationModel = r.ko.Spec.ImportExpirationModel
+	if r.ko.Status.ExpirationModel == nil {
+		expirationModel := string(svcsdktypes.ExpirationModelTypeKeyMaterialExpires)
+		r.ko.Status.ExpirationModel = &expirationModel
+	}
+	r.ko.Status.ValidTo = validTo
+	clearImportParameters(r.ko)
+	return nil
+}
+
+// importValidTo returns the expiration date of key material imported at now.
+// Spec.ImportValidityInDays takes precedence over Spec.ImportValidTo.
+func importValidTo(ko *svcapitypes.Key, now time.Time) *metav1.Time {
+	if ko.Spec.ImportValidityInDays != nil {
+		validTo := metav1.NewTime(now.AddDate(0, 0, int(*ko.Spec.ImportValidityInDays)))
+		return &validTo
+	}
+	return ko.Spec.ImportValidTo
+}
+
+// keyMaterialExpires returns true if the imported key material of the KMS key
+// has an expiration date.
+func keyMaterialExpires(ko *svcapitypes.Key) bool {
+	return ko.Status.ExpirationModel != nil &&
+		*ko.Status.ExpirationModel == string(svcsdktypes.ExpirationModelTypeKeyMaterialExpires) &&
+		ko.Status.ValidTo != nil
+}
+
+// isKeyMaterialReimportDue returns true if Spec.ReimportKeyMaterialBeforeExpiryInDays
+// asks for the key material of the KMS key to be re-imported by now.
+func isKeyMaterialReimportDue(ko *svcapitypes.Key, now time.Time) bool {
+	if ko.Spec.ReimportKeyMaterialBeforeExpiryInDays == nil ||
+		ko.Spec.EncryptedKeyMaterial == nil ||
+		!keyMaterialExpires(ko) {
+		return false
+	}
+	reimportAt := ko.Status.ValidTo.AddDate(0, 0, -int(*ko.Spec.ReimportKeyMaterialBeforeExpiryInDays))
+	return !now.Before(reimportAt)
+}
+
+// canReimportKeyMaterial returns true if key material re-imported at now
+// would expire after the current key material.
+func canReimportKeyMaterial(ko *svcapitypes.Key, now time.Time) bool {
+	validTo := importValidTo(ko, now)
+	return validTo != nil && validTo.After(ko.Status.ValidTo.Time)
+}
+
+// syncKeyMaterialExpiry keeps track of the expiration date of the imported key
+// material of the KMS key. When it is due, the key material is re-imported
+// from the Secret referenced by Spec.EncryptedKeyMaterial, and the
+// KMS.KeyMaterialExpiring condition warns about key material that expires
+// soon. Synced Keys are requeued every 6 hours (reconcile.requeue_on_success_seconds
+// in generator.yaml), well ahead of the thresholds and re-import point, which
+// are expressed in days.
+func (rm *resourceManager) syncKeyMaterialExpiry(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.syncKeyMaterialExpiry")
+	defer func() {
+		exit(err)
+	}()
+	now := time.Now()
+	if isKeyMaterialReimportDue(r.ko, now) && canReimportKeyMaterial(r.ko, now) {
+		if err = rm.reimportKeyMaterial(ctx, r, now); err != nil {
+			return err
+		}
+	}
+	setKeyMaterialExpiringCondition(r.ko, now)
+	return nil
+}
+
+// reimportKeyMaterial re-imports the key material of the KMS key before it
+// expires. Key material is wrapped with the public key of a single set of
+// import parameters, so the key material in the Secret is only imported once
+// it has been wrapped again with the public key published for the re-import.
+func (rm *resourceManager) reimportKeyMaterial(ctx context.Context, r *resource, now time.Time) error {
+	if importParametersExpired(r.ko) {
+		if err := rm.getParametersForImport(ctx, r); err != nil {
+			return err
+		}
+		if err := rm.publishImportParameters(ctx, r); err != nil {
+			clearImportParameters(r.ko)
+			return ackrequeue.Needed(err)
+		}
+	}
+	err := rm.importKeyMaterial(ctx, r, importValidTo(r.ko, now))
+	var awsErr smithy.APIError
+	if errors.As(err, &awsErr) && awsErr.ErrorCode() == "InvalidCiphertextException" {
+		// The key material in the Secret is still wrapped with the public
+		// key of the previous import
+		return nil
+	}
+	return err
+}
+
+// setKeyMaterialExpiringCondition sets the KMS.KeyMaterialExpiring condition
+// of the resource when its imported key material expires within one of the
+// thresholds of Spec.KeyMaterialExpiryWarningDays, or removes it otherwise.
+func setKeyMaterialExpiringCondition(ko *svcapitypes.Key, now time.Time) {
+	r := &resource{ko}
+	threshold, ok := keyMaterialExpiryThreshold(ko, now)
+	if !ok {
+		conditions := []*ackv1alpha1.Condition{}
+		for _, c := range r.Conditions() {
+			if c.Type != svcapitypes.ConditionTypeKeyMaterialExpiring {
+				conditions = append(conditions, c)
+			}
+		}
+		r.ReplaceConditions(conditions)
+		return
+	}
+
+	msg := fmt.Sprintf("The imported key material expires at %s", ko.Status.ValidTo.UTC().Format(time.RFC3339))
+	if isKeyMaterialReimportDue(ko, now) {
+		if canReimportKeyMaterial(ko, now) {
+			msg += fmt.Sprintf(
+				", it is re-imported once the Secret %s holds key material wrapped with the public key in the status",
+				ko.Spec.EncryptedKeyMaterial.Name,
+			)
+		} else {
+			msg += ", spec.importValidityInDays or a later spec.importValidTo is required to re-import it"
+		}
+	}
+	reason := fmt.Sprintf("ExpiresWithin%dDays", threshold)
+
+	allConds := r.Conditions()
+	c := ackcondition.FirstOfType(r, svcapitypes.ConditionTypeKeyMaterialExpiring)
+	if c == nil {
+		c = &ackv1alpha1.Condition{
+			Type: svcapitypes.ConditionTypeKeyMaterialExpiring,
+		}
+		allConds = append(allConds, c)
+	}
+	if c.Status != corev1.ConditionTrue {
+		c.LastTransitionTime = &metav1.Time{Time: now}
+	}
+	c.Status = corev1.ConditionTrue
+	c.Message = &msg
+	c.Reason = &reason
+	r.ReplaceConditions(allConds)
+}
+
+// keyMaterialExpiryThreshold returns the smallest threshold, in days, of
+// Spec.KeyMaterialExpiryWarningDays within which the imported key material of
+// the KMS key expires. ok is false when no threshold is reached.
+func keyMaterialExpiryThreshold(ko *svcapitypes.Key, now time.Time) (threshold int64, ok bool) {
+	if !keyMaterialExpires(ko) {
+		return 0, false
+	}
+	for _, days := range keyMaterialExpiryWarningDays(ko) {
+		if now.Before(ko.Status.ValidTo.AddDate(0, 0, -int(days))) {
+			continue
+		}
+		if !ok || days < threshold {
+			threshold, ok = days, true
+		}
+	}
+	return threshold, ok
+}
+
+// keyMaterialExpiryWarningDays returns the thresholds, in days, of
+// Spec.KeyMaterialExpiryWarningDays, or DefaultKeyMaterialExpiryWarningDays
+// when it is not set.
+func keyMaterialExpiryWarningDays(ko *svcapitypes.Key) []int64 {
+	if ko.Spec.KeyMaterialExpiryWarningDays == nil {
+		return DefaultKeyMaterialExpiryWarningDays
+	}
+	thresholds := []int64{}
+	for _, days := range ko.Spec.KeyMaterialExpiryWarningDays {
+		if days != nil {
+			thresholds = append(thresholds, *days)
+		}
+	}
+	return thresholds
+}
+
+// clearImportParameters removes the import parameters from the status of the
+// resource.
+func clearImportParameters(ko *svcapitypes.Key) {
+	ko.Status.ImportToken = nil
+	ko.Status.PublicKey = nil
+	ko.Status.ImportParametersValidTo = nil
+}
@@ -50,7 +50,7 @@ var (
 // +kubebuilder:rbac:groups=kms.services.k8s.aws,resources=keys,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kms.services.k8s.aws,resources=keys/status,verbs=get;update;patch
 
-var lateInitializeFieldNames = []string{}
+var lateInitializeFieldNames = []string{"Enabled"}
 
 // resourceManager is responsible for providing a consistent way to perform
 // CRUD operations in a backend AWS service API for Book custom resources.
@@ -251,6 +251,10 @@ func (rm *resourceManager) LateInitialize(
 func (rm *resourceManager) incompleteLateInitialization(
 	res acktypes.AWSResource,
 ) bool {
+	ko := rm.concreteResource(res).ko.DeepCopy()
+	if ko.Spec.Enabled == nil {
+		return true
+	}
 	return false
 }
 
@@ -260,7 +264,12 @@ func (rm *resourceManager) lateInitializeFromReadOneOutput(
 	observed acktypes.AWSResource,
 	latest acktypes.AWSResource,
 ) acktypes.AWSResource {
-	return latest
+	observedKo := rm.concreteResource(observed).ko
+	latestKo := rm.concreteResource(latest).ko
+	if observedKo.Spec.Enabled != nil && latestKo.Spec.Enabled == nil {
+		latestKo.Spec.Enabled = observedKo.Spec.Enabled
+	}
+	return &resource{latestKo}
 }
 
 // IsSynced returns true if the resource is synced.
@@ -271,6 +280,14 @@ func (rm *resourceManager) IsSynced(ctx context.Context, res acktypes.AWSResourc
 		panic("resource manager's IsSynced() method received resource with nil CR object")
 	}
 
+	if r.ko.Status.KeyState == nil {
+		return false, nil
+	}
+	keyStateCandidates := []string{"Enabled", "Disabled"}
+	if !ackutil.InStrings(*r.ko.Status.KeyState, keyStateCandidates) {
+		return false, nil
+	}
+
 	return true, nil
 }
 
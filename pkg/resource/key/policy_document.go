@@ -0,0 +1,320 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+const (
+	// PolicyVersion is the version of the policy language used when a
+	// structured key policy does not specify one.
+	PolicyVersion = "2012-10-17"
+
+	principalAWS       = "AWS"
+	principalFederated = "Federated"
+	principalService   = "Service"
+)
+
+// policyJSON is the JSON key policy document a KeyPolicyDocument renders to.
+type policyJSON struct {
+	Version   string        `json:"Version"`
+	ID        string        `json:"Id,omitempty"`
+	Statement statementList `json:"Statement"`
+}
+
+// statementJSON is a statement of a JSON key policy document. Statements
+// using elements that have no structured equivalent, such as NotAction or
+// NotPrincipal, are rejected when parsing.
+type statementJSON struct {
+	Sid       string                           `json:"Sid,omitempty"`
+	Effect    string                           `json:"Effect"`
+	Principal principalMap                     `json:"Principal,omitempty"`
+	Action    stringList                       `json:"Action"`
+	Resource  stringList                       `json:"Resource"`
+	Condition map[string]map[string]stringList `json:"Condition,omitempty"`
+}
+
+// statementList is the Statement element of a key policy, which is either a
+// single statement or a list of statements.
+type statementList []statementJSON
+
+func (l *statementList) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		return strictUnmarshal(b, (*[]statementJSON)(l))
+	}
+	var statement statementJSON
+	if err := strictUnmarshal(b, &statement); err != nil {
+		return err
+	}
+	*l = statementList{statement}
+	return nil
+}
+
+// principalMap is the Principal element of a statement, which is either "*"
+// or a map of principal types to principals.
+type principalMap map[string]stringList
+
+func (m *principalMap) UnmarshalJSON(b []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(b, &wildcard); err == nil {
+		if wildcard != "*" {
+			return fmt.Errorf("unexpected principal %q", wildcard)
+		}
+		*m = principalMap{principalAWS: stringList{"*"}}
+		return nil
+	}
+	return json.Unmarshal(b, (*map[string]stringList)(m))
+}
+
+// stringList is a policy element that is either a single value or a list of
+// values. Boolean and numeric condition values are kept as strings.
+type stringList []string
+
+func (l stringList) MarshalJSON() ([]byte, error) {
+	if len(l) == 1 {
+		return json.Marshal(l[0])
+	}
+	return json.Marshal([]string(l))
+}
+
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	values, ok := raw.([]interface{})
+	if !ok {
+		values = []interface{}{raw}
+	}
+	*l = make(stringList, 0, len(values))
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			*l = append(*l, v)
+		case json.Number:
+			*l = append(*l, v.String())
+		case bool:
+			*l = append(*l, fmt.Sprint(v))
+		default:
+			return fmt.Errorf("unexpected policy value %v", v)
+		}
+	}
+	return nil
+}
+
+// strictUnmarshal decodes JSON into v, failing on fields v does not have.
+func strictUnmarshal(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// renderPolicyDocument renders a key policy authored as structured statements
+// to the JSON key policy document KMS expects.
+func renderPolicyDocument(doc *svcapitypes.KeyPolicyDocument) (string, error) {
+	policy := policyJSON{
+		Version:   PolicyVersion,
+		ID:        aws.ToString(doc.ID),
+		Statement: statementList{},
+	}
+	if doc.Version != nil {
+		policy.Version = *doc.Version
+	}
+	for _, s := range doc.Statements {
+		if s == nil {
+			continue
+		}
+		statement := statementJSON{
+			Sid:      aws.ToString(s.SID),
+			Effect:   aws.ToString(s.Effect),
+			Action:   aws.ToStringSlice(s.Actions),
+			Resource: aws.ToStringSlice(s.Resources),
+		}
+		if len(statement.Resource) == 0 {
+			statement.Resource = stringList{"*"}
+		}
+		if s.Principals != nil {
+			statement.Principal = principalMap{}
+			for principalType, principals := range map[string][]*string{
+				principalAWS:       s.Principals.AWS,
+				principalFederated: s.Principals.Federated,
+				principalService:   s.Principals.Service,
+			} {
+				if len(principals) > 0 {
+					statement.Principal[principalType] = aws.ToStringSlice(principals)
+				}
+			}
+		}
+		for _, c := range s.Conditions {
+			if c == nil {
+				continue
+			}
+			if statement.Condition == nil {
+				statement.Condition = map[string]map[string]stringList{}
+			}
+			operator := aws.ToString(c.Operator)
+			if statement.Condition[operator] == nil {
+				statement.Condition[operator] = map[string]stringList{}
+			}
+			key := aws.ToString(c.Key)
+			statement.Condition[operator][key] = append(
+				statement.Condition[operator][key], aws.ToStringSlice(c.Values)...,
+			)
+		}
+		policy.Statement = append(policy.Statement, statement)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(policy); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// parsePolicyDocument parses a JSON key policy document into structured
+// statements. It returns an error if the key policy uses elements that have
+// no structured equivalent.
+func parsePolicyDocument(policy string) (*svcapitypes.KeyPolicyDocument, error) {
+	var p policyJSON
+	if err := strictUnmarshal([]byte(policy), &p); err != nil {
+		return nil, fmt.Errorf("cannot parse key policy: %w", err)
+	}
+	doc := &svcapitypes.KeyPolicyDocument{
+		Statements: []*svcapitypes.KeyPolicyStatement{},
+	}
+	if p.Version != "" {
+		doc.Version = aws.String(p.Version)
+	}
+	if p.ID != "" {
+		doc.ID = aws.String(p.ID)
+	}
+	for _, s := range p.Statement {
+		statement := &svcapitypes.KeyPolicyStatement{
+			Effect:    aws.String(s.Effect),
+			Actions:   aws.StringSlice(s.Action),
+			Resources: aws.StringSlice(s.Resource),
+		}
+		if s.Sid != "" {
+			statement.SID = aws.String(s.Sid)
+		}
+		if len(s.Principal) > 0 {
+			statement.Principals = &svcapitypes.KeyPolicyPrincipals{}
+			for principalType, principals := range s.Principal {
+				switch principalType {
+				case principalAWS:
+					statement.Principals.AWS = aws.StringSlice(principals)
+				case principalFederated:
+					statement.Principals.Federated = aws.StringSlice(principals)
+				case principalService:
+					statement.Principals.Service = aws.StringSlice(principals)
+				default:
+					return nil, fmt.Errorf("cannot parse key policy: unsupported principal type %q", principalType)
+				}
+			}
+		}
+		for _, operator := range sortedKeys(s.Condition) {
+			for _, key := range sortedKeys(s.Condition[operator]) {
+				statement.Conditions = append(statement.Conditions, &svcapitypes.KeyPolicyCondition{
+					Operator: aws.String(operator),
+					Key:      aws.String(key),
+					Values:   aws.StringSlice(s.Condition[operator][key]),
+				})
+			}
+		}
+		doc.Statements = append(doc.Statements, statement)
+	}
+	return doc, nil
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// desiredPolicy returns the JSON key policy document of the resource,
+// rendering Spec.PolicyDocument when the key policy is authored as structured
+// statements.
+func desiredPolicy(ko *svcapitypes.Key) (*string, error) {
+	if ko.Spec.PolicyDocument == nil {
+		return ko.Spec.Policy, nil
+	}
+	policy, err := renderPolicyDocument(ko.Spec.PolicyDocument)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// setPolicy sets the key policy read from KMS on the resource, as structured
+// statements when the resource authors its key policy that way.
+func setPolicy(ko *svcapitypes.Key, policy *string) {
+	if ko.Spec.PolicyDocument == nil || policy == nil {
+		ko.Spec.Policy = policy
+		return
+	}
+	doc, err := parsePolicyDocument(*policy)
+	if err != nil {
+		// A key policy that cannot be represented as structured statements
+		// never matches the desired one, so it is replaced.
+		doc = &svcapitypes.KeyPolicyDocument{}
+	}
+	ko.Spec.PolicyDocument = doc
+}
+
+// comparePolicyDocument adds a difference at Spec.PolicyDocument to the delta
+// when the two structured key policies render to key policy documents that
+// are not equivalent. Statement order and single-value versus list elements
+// do not matter.
+func comparePolicyDocument(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	if ackcompare.HasNilDifference(a.ko.Spec.PolicyDocument, b.ko.Spec.PolicyDocument) {
+		delta.Add("Spec.PolicyDocument", a.ko.Spec.PolicyDocument, b.ko.Spec.PolicyDocument)
+		return
+	}
+	if a.ko.Spec.PolicyDocument == nil {
+		return
+	}
+	aPolicy, aErr := renderPolicyDocument(a.ko.Spec.PolicyDocument)
+	bPolicy, bErr := renderPolicyDocument(b.ko.Spec.PolicyDocument)
+	if aErr != nil || bErr != nil {
+		delta.Add("Spec.PolicyDocument", a.ko.Spec.PolicyDocument, b.ko.Spec.PolicyDocument)
+		return
+	}
+	if equal, err := ackcompare.IAMPolicyDocumentEqual(aPolicy, bPolicy); err != nil || !equal {
+		delta.Add("Spec.PolicyDocument", a.ko.Spec.PolicyDocument, b.ko.Spec.PolicyDocument)
+	}
+}
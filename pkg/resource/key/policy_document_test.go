@@ -0,0 +1,252 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+// testPolicyDocument returns a structured key policy granting the account
+// root full access and a role the right to use the key from one account.
+func testPolicyDocument() *svcapitypes.KeyPolicyDocument {
+	return &svcapitypes.KeyPolicyDocument{
+		Statements: []*svcapitypes.KeyPolicyStatement{
+			{
+				SID:     aws.String("EnableRootAccess"),
+				Effect:  aws.String("Allow"),
+				Actions: aws.StringSlice([]string{"kms:*"}),
+				Principals: &svcapitypes.KeyPolicyPrincipals{
+					AWS: aws.StringSlice([]string{"arn:aws:iam::111122223333:root"}),
+				},
+			},
+			{
+				SID:     aws.String("AllowUse"),
+				Effect:  aws.String("Allow"),
+				Actions: aws.StringSlice([]string{"kms:Encrypt", "kms:Decrypt"}),
+				Principals: &svcapitypes.KeyPolicyPrincipals{
+					AWS: aws.StringSlice([]string{"arn:aws:iam::111122223333:role/app"}),
+				},
+				Conditions: []*svcapitypes.KeyPolicyCondition{
+					{
+						Operator: aws.String("StringEquals"),
+						Key:      aws.String("kms:CallerAccount"),
+						Values:   aws.StringSlice([]string{"111122223333"}),
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestKeyWithPolicyDocument(doc *svcapitypes.KeyPolicyDocument) *resource {
+	r := newTestKey(nil)
+	r.ko.Spec.PolicyDocument = doc
+	return r
+}
+
+func TestRenderPolicyDocument(t *testing.T) {
+	policy, err := renderPolicyDocument(testPolicyDocument())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Version": "2012-10-17",
+		"Statement": [
+			{
+				"Sid": "EnableRootAccess",
+				"Effect": "Allow",
+				"Principal": {"AWS": "arn:aws:iam::111122223333:root"},
+				"Action": "kms:*",
+				"Resource": "*"
+			},
+			{
+				"Sid": "AllowUse",
+				"Effect": "Allow",
+				"Principal": {"AWS": "arn:aws:iam::111122223333:role/app"},
+				"Action": ["kms:Encrypt", "kms:Decrypt"],
+				"Resource": "*",
+				"Condition": {"StringEquals": {"kms:CallerAccount": "111122223333"}}
+			}
+		]
+	}`, policy)
+}
+
+func TestParsePolicyDocument(t *testing.T) {
+	// The default key policy as returned by GetKeyPolicy
+	doc, err := parsePolicyDocument(`{
+		"Version" : "2012-10-17",
+		"Id" : "key-default-1",
+		"Statement" : [ {
+			"Sid" : "Enable IAM User Permissions",
+			"Effect" : "Allow",
+			"Principal" : {
+				"AWS" : "arn:aws:iam::111122223333:root"
+			},
+			"Action" : "kms:*",
+			"Resource" : "*"
+		} ]
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, "2012-10-17", *doc.Version)
+	assert.Equal(t, "key-default-1", *doc.ID)
+	require.Len(t, doc.Statements, 1)
+	statement := doc.Statements[0]
+	assert.Equal(t, "Enable IAM User Permissions", *statement.SID)
+	assert.Equal(t, []string{"arn:aws:iam::111122223333:root"}, aws.ToStringSlice(statement.Principals.AWS))
+	assert.Equal(t, []string{"kms:*"}, aws.ToStringSlice(statement.Actions))
+	assert.Equal(t, []string{"*"}, aws.ToStringSlice(statement.Resources))
+
+	// A single statement, a wildcard principal and a boolean condition value
+	doc, err = parsePolicyDocument(`{
+		"Version": "2012-10-17",
+		"Statement": {
+			"Effect": "Deny",
+			"Principal": "*",
+			"Action": "kms:*",
+			"Resource": "*",
+			"Condition": {"Bool": {"aws:SecureTransport": false}}
+		}
+	}`)
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 1)
+	statement = doc.Statements[0]
+	assert.Equal(t, []string{"*"}, aws.ToStringSlice(statement.Principals.AWS))
+	require.Len(t, statement.Conditions, 1)
+	assert.Equal(t, "Bool", *statement.Conditions[0].Operator)
+	assert.Equal(t, "aws:SecureTransport", *statement.Conditions[0].Key)
+	assert.Equal(t, []string{"false"}, aws.ToStringSlice(statement.Conditions[0].Values))
+
+	// Elements without a structured equivalent are rejected
+	_, err = parsePolicyDocument(`{
+		"Version": "2012-10-17",
+		"Statement": [{"Effect": "Allow", "Principal": "*", "NotAction": "kms:Decrypt", "Resource": "*"}]
+	}`)
+	assert.Error(t, err)
+}
+
+func TestNewResourceDelta_PolicyDocument(t *testing.T) {
+	rendered, err := renderPolicyDocument(testPolicyDocument())
+	require.NoError(t, err)
+	// KMS returns the key policy it was given, reformatted
+	roundTripped, err := parsePolicyDocument(rendered)
+	require.NoError(t, err)
+
+	reordered := testPolicyDocument()
+	reordered.Statements[0], reordered.Statements[1] = reordered.Statements[1], reordered.Statements[0]
+	reordered.Statements[0].Resources = aws.StringSlice([]string{"*"})
+	reordered.Version = aws.String(PolicyVersion)
+
+	changed := testPolicyDocument()
+	changed.Statements[1].Actions = append(changed.Statements[1].Actions, aws.String("kms:GenerateDataKey"))
+
+	tests := []struct {
+		name        string
+		desired     *svcapitypes.KeyPolicyDocument
+		latest      *svcapitypes.KeyPolicyDocument
+		expectDelta bool
+	}{
+		{
+			name:        "round trip through KMS",
+			desired:     testPolicyDocument(),
+			latest:      roundTripped,
+			expectDelta: false,
+		},
+		{
+			name:        "statement order and defaults",
+			desired:     testPolicyDocument(),
+			latest:      reordered,
+			expectDelta: false,
+		},
+		{
+			name:        "actions changed",
+			desired:     changed,
+			latest:      roundTripped,
+			expectDelta: true,
+		},
+		{
+			name:        "policy that cannot be represented",
+			desired:     testPolicyDocument(),
+			latest:      &svcapitypes.KeyPolicyDocument{},
+			expectDelta: true,
+		},
+		{
+			name:        "not authored as structured statements",
+			desired:     nil,
+			latest:      nil,
+			expectDelta: false,
+		},
+		{
+			name:        "switched to structured statements",
+			desired:     testPolicyDocument(),
+			latest:      nil,
+			expectDelta: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := newResourceDelta(
+				newTestKeyWithPolicyDocument(tt.desired),
+				newTestKeyWithPolicyDocument(tt.latest),
+			)
+			assert.Equal(t, tt.expectDelta, delta.DifferentAt("Spec.PolicyDocument"))
+		})
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"}]}`
+
+	// Keys authored with a JSON key policy read it back as JSON
+	ko := newTestKey(nil).ko
+	setPolicy(ko, &policy)
+	assert.Equal(t, policy, *ko.Spec.Policy)
+	assert.Nil(t, ko.Spec.PolicyDocument)
+
+	ko = newTestKeyWithPolicyDocument(testPolicyDocument()).ko
+	setPolicy(ko, &policy)
+	assert.Nil(t, ko.Spec.Policy)
+	require.Len(t, ko.Spec.PolicyDocument.Statements, 1)
+	assert.Equal(t, []string{"kms:*"}, aws.ToStringSlice(ko.Spec.PolicyDocument.Statements[0].Actions))
+
+	unsupported := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"}]}`
+	ko = newTestKeyWithPolicyDocument(testPolicyDocument()).ko
+	setPolicy(ko, &unsupported)
+	assert.Empty(t, ko.Spec.PolicyDocument.Statements)
+}
+
+func TestCustomUpdate_PolicyDocument(t *testing.T) {
+	desired := newTestKeyWithPolicyDocument(testPolicyDocument())
+	latest := newTestKeyWithPolicyDocument(&svcapitypes.KeyPolicyDocument{})
+	delta := newResourceDelta(desired, latest)
+
+	fake := newFakeSDKAPI()
+	rm := newFakeResourceManager(fake)
+	updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+	require.NoError(t, err)
+	// The default key policy can be restored once the key policy is removed
+	assert.True(t, *updated.ko.Status.PolicyApplied)
+
+	require.True(t, fake.Called("PutKeyPolicy"))
+	input := fake.Inputs["PutKeyPolicy"].(*svcsdk.PutKeyPolicyInput)
+	expected, err := renderPolicyDocument(testPolicyDocument())
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, *input.Policy)
+	assert.Equal(t, PolicyName, *input.PolicyName)
+}
@@ -0,0 +1,236 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+const (
+	// PolicyDriftModeEnforce updates the key policy to the desired one.
+	PolicyDriftModeEnforce = "enforce"
+	// PolicyDriftModeObserve leaves the key policy as is and reports
+	// differences in the KMS.PolicyDrifted condition.
+	PolicyDriftModeObserve = "observe"
+	// PolicyDriftModeAdopt makes the key policy of the KMS key the desired
+	// one.
+	PolicyDriftModeAdopt = "adopt"
+
+	// ConditionReasonPolicyDrifted is the reason of the KMS.PolicyDrifted
+	// condition.
+	ConditionReasonPolicyDrifted = "StatementsDiffer"
+
+	// maxPolicyDriftStatements is the number of statements listed per kind of
+	// difference in the message of the KMS.PolicyDrifted condition.
+	maxPolicyDriftStatements = 5
+)
+
+// policyDriftMode returns the Spec.PolicyDriftMode of the resource, which
+// defaults to enforce.
+func policyDriftMode(ko *svcapitypes.Key) string {
+	if ko.Spec.PolicyDriftMode == nil || *ko.Spec.PolicyDriftMode == "" {
+		return PolicyDriftModeEnforce
+	}
+	return *ko.Spec.PolicyDriftMode
+}
+
+// syncPolicyDrift sets the key policy read from KMS on the latest resource
+// according to the Spec.PolicyDriftMode of the desired resource, whose key
+// policy with its key policy fragments merged is policy. In observe mode the
+// latest resource keeps the desired key policy, so that it is never updated,
+// and the KMS.PolicyDrifted condition reports the differences. In adopt mode
+// a key policy that cannot be represented as the structured statements of
+// Spec.PolicyDocument is set as Spec.Policy instead.
+//
+// The key policy fragments are not part of the authored key policy, so with
+// Spec.PolicyRefs the latest resource keeps the desired key policy when the
+// key policy is the merged one, and has no Spec.PolicyRefs otherwise.
+func syncPolicyDrift(
+	desired *svcapitypes.Key,
+	latest *svcapitypes.Key,
+	policy *string,
+	observed *string,
+) error {
+	mode := policyDriftMode(desired)
+	if mode != PolicyDriftModeObserve && len(desired.Spec.PolicyRefs) > 0 {
+		if policy != nil && observed != nil {
+			if equal, err := ackcompare.IAMPolicyDocumentEqual(*policy, *observed); err == nil && equal {
+				latest.Spec.Policy = desired.Spec.Policy
+				latest.Spec.PolicyDocument = desired.Spec.PolicyDocument.DeepCopy()
+				setPolicyDriftedCondition(latest, "", time.Now())
+				return nil
+			}
+		}
+		latest.Spec.PolicyRefs = nil
+	}
+	switch mode {
+	case PolicyDriftModeObserve:
+		latest.Spec.Policy = desired.Spec.Policy
+		latest.Spec.PolicyDocument = desired.Spec.PolicyDocument.DeepCopy()
+		if policy == nil || *policy == "" || observed == nil {
+			setPolicyDriftedCondition(latest, "", time.Now())
+			return nil
+		}
+		summary, err := summarizePolicyDrift(*policy, *observed)
+		if err != nil {
+			return err
+		}
+		setPolicyDriftedCondition(latest, summary, time.Now())
+	case PolicyDriftModeAdopt:
+		setPolicy(latest, observed)
+		if latest.Spec.PolicyDocument != nil && len(latest.Spec.PolicyDocument.Statements) == 0 {
+			latest.Spec.Policy = observed
+			latest.Spec.PolicyDocument = nil
+		}
+		setPolicyDriftedCondition(latest, "", time.Now())
+	default:
+		setPolicy(latest, observed)
+		setPolicyDriftedCondition(latest, "", time.Now())
+	}
+	return nil
+}
+
+// summarizePolicyDrift returns a summary of the statements that differ
+// between the desired and the observed JSON key policies, or "" if they are
+// equivalent. Statements are named by their Sid, or by their position in the
+// key policy when they have none.
+func summarizePolicyDrift(desired string, observed string) (string, error) {
+	if equal, err := ackcompare.IAMPolicyDocumentEqual(desired, observed); err == nil && equal {
+		return "", nil
+	}
+	desiredStatements, err := policyStatements(desired)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse desired key policy: %w", err)
+	}
+	observedStatements, err := policyStatements(observed)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse key policy: %w", err)
+	}
+	missing := unmatchedStatements(desiredStatements, observedStatements)
+	unexpected := unmatchedStatements(observedStatements, desiredStatements)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		// Only elements outside of the statements, such as Id, differ
+		return "key policy differs from the desired key policy outside of its statements", nil
+	}
+	parts := []string{}
+	if len(missing) > 0 {
+		parts = append(parts, "missing "+listStatements(missing))
+	}
+	if len(unexpected) > 0 {
+		parts = append(parts, "unexpected "+listStatements(unexpected))
+	}
+	return "key policy differs from the desired key policy: " + strings.Join(parts, "; "), nil
+}
+
+// policyStatements returns the statements of a JSON key policy.
+func policyStatements(policy string) ([]json.RawMessage, error) {
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return nil, err
+	}
+	return rawStatements(doc["Statement"])
+}
+
+// unmatchedStatements returns the names of the statements of a that have no
+// equivalent statement in b.
+func unmatchedStatements(a []json.RawMessage, b []json.RawMessage) []string {
+	names := []string{}
+	for i, statement := range a {
+		matched := false
+		for _, other := range b {
+			if statementsEqual(statement, other) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if sid := statementSid(statement); sid != "" {
+			names = append(names, fmt.Sprintf("%q", sid))
+		} else {
+			names = append(names, fmt.Sprintf("#%d", i+1))
+		}
+	}
+	return names
+}
+
+// statementsEqual returns true if the two key policy statements are
+// equivalent.
+func statementsEqual(a json.RawMessage, b json.RawMessage) bool {
+	wrap := func(statement json.RawMessage) string {
+		return `{"Version":"` + PolicyVersion + `","Statement":[` + string(statement) + `]}`
+	}
+	equal, err := ackcompare.IAMPolicyDocumentEqual(wrap(a), wrap(b))
+	return err == nil && equal
+}
+
+// listStatements returns a human readable list of statement names, truncated
+// to maxPolicyDriftStatements names.
+func listStatements(names []string) string {
+	noun := "statements"
+	if len(names) == 1 {
+		noun = "statement"
+	}
+	if len(names) > maxPolicyDriftStatements {
+		more := len(names) - maxPolicyDriftStatements
+		names = append(names[:maxPolicyDriftStatements:maxPolicyDriftStatements], fmt.Sprintf("%d more", more))
+	}
+	return noun + " " + strings.Join(names, ", ")
+}
+
+// setPolicyDriftedCondition sets the KMS.PolicyDrifted condition of the
+// resource with the supplied summary of the differences, or removes it when
+// there are none.
+func setPolicyDriftedCondition(ko *svcapitypes.Key, summary string, now time.Time) {
+	r := &resource{ko}
+	if summary == "" {
+		conditions := []*ackv1alpha1.Condition{}
+		for _, c := range r.Conditions() {
+			if c.Type != svcapitypes.ConditionTypePolicyDrifted {
+				conditions = append(conditions, c)
+			}
+		}
+		r.ReplaceConditions(conditions)
+		return
+	}
+
+	allConds := r.Conditions()
+	c := ackcondition.FirstOfType(r, svcapitypes.ConditionTypePolicyDrifted)
+	if c == nil {
+		c = &ackv1alpha1.Condition{
+			Type: svcapitypes.ConditionTypePolicyDrifted,
+		}
+		allConds = append(allConds, c)
+	}
+	if c.Status != corev1.ConditionTrue {
+		c.LastTransitionTime = &metav1.Time{Time: now}
+	}
+	reason := ConditionReasonPolicyDrifted
+	c.Status = corev1.ConditionTrue
+	c.Message = &summary
+	c.Reason = &reason
+	r.ReplaceConditions(allConds)
+}
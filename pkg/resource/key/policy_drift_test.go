@@ -0,0 +1,172 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+const (
+	desiredDriftPolicy = `{"Version":"2012-10-17","Statement":[
+		{"Sid":"EnableRootAccess","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"},
+		{"Sid":"AllowUse","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":["kms:Encrypt","kms:Decrypt"],"Resource":"*"}
+	]}`
+	// driftedPolicy is desiredDriftPolicy as edited outside of the controller
+	driftedPolicy = `{"Version":"2012-10-17","Statement":[
+		{"Sid":"EnableRootAccess","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"},
+		{"Sid":"AllowUse","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":["kms:Encrypt","kms:Decrypt","kms:ReEncrypt*"],"Resource":"*"},
+		{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::444455556666:root"},"Action":"kms:Decrypt","Resource":"*"}
+	]}`
+)
+
+func newTestKeyWithPolicy(policy *string) *resource {
+	r := newTestKey(nil)
+	r.ko.Spec.Policy = policy
+	return r
+}
+
+func TestSummarizePolicyDrift(t *testing.T) {
+	// Equivalent key policies do not drift
+	reordered := `{"Version":"2012-10-17","Statement":[
+		{"Sid":"AllowUse","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::111122223333:role/app"]},"Action":["kms:Decrypt","kms:Encrypt"],"Resource":["*"]},
+		{"Sid":"EnableRootAccess","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"}
+	]}`
+	summary, err := summarizePolicyDrift(desiredDriftPolicy, reordered)
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+
+	summary, err = summarizePolicyDrift(desiredDriftPolicy, driftedPolicy)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`key policy differs from the desired key policy: missing statement "AllowUse"; unexpected statements "AllowUse", #3`,
+		summary,
+	)
+
+	_, err = summarizePolicyDrift(desiredDriftPolicy, `not json`)
+	assert.Error(t, err)
+
+	assert.Equal(t, "statements a, b, c, d, e, 2 more", listStatements([]string{"a", "b", "c", "d", "e", "f", "g"}))
+}
+
+func TestSyncPolicyDrift(t *testing.T) {
+	unstructurable := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":"kms:*","Resource":"*"}]}`
+	tests := []struct {
+		name            string
+		mode            *string
+		desired         *resource
+		observed        string
+		expectPolicy    *string
+		expectDocument  bool
+		expectCondition bool
+	}{
+		{
+			name:         "enforce by default",
+			desired:      newTestKeyWithPolicy(aws.String(desiredDriftPolicy)),
+			observed:     driftedPolicy,
+			expectPolicy: aws.String(driftedPolicy),
+		},
+		{
+			name:         "enforce",
+			mode:         aws.String(PolicyDriftModeEnforce),
+			desired:      newTestKeyWithPolicy(aws.String(desiredDriftPolicy)),
+			observed:     driftedPolicy,
+			expectPolicy: aws.String(driftedPolicy),
+		},
+		{
+			name:            "observe",
+			mode:            aws.String(PolicyDriftModeObserve),
+			desired:         newTestKeyWithPolicy(aws.String(desiredDriftPolicy)),
+			observed:        driftedPolicy,
+			expectPolicy:    aws.String(desiredDriftPolicy),
+			expectCondition: true,
+		},
+		{
+			name:            "observe structured statements",
+			mode:            aws.String(PolicyDriftModeObserve),
+			desired:         newTestKeyWithPolicyDocument(testPolicyDocument()),
+			observed:        driftedPolicy,
+			expectDocument:  true,
+			expectCondition: true,
+		},
+		{
+			name:         "adopt",
+			mode:         aws.String(PolicyDriftModeAdopt),
+			desired:      newTestKeyWithPolicy(aws.String(desiredDriftPolicy)),
+			observed:     driftedPolicy,
+			expectPolicy: aws.String(driftedPolicy),
+		},
+		{
+			name:         "adopt a key policy that cannot be structured",
+			mode:         aws.String(PolicyDriftModeAdopt),
+			desired:      newTestKeyWithPolicyDocument(testPolicyDocument()),
+			observed:     unstructurable,
+			expectPolicy: aws.String(unstructurable),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.desired.ko.Spec.PolicyDriftMode = tt.mode
+			latest := tt.desired.ko.DeepCopy()
+			policy, err := desiredPolicy(tt.desired.ko)
+			require.NoError(t, err)
+			require.NoError(t, syncPolicyDrift(tt.desired.ko, latest, policy, aws.String(tt.observed)))
+
+			assert.Equal(t, tt.expectPolicy, latest.Spec.Policy)
+			assert.Equal(t, tt.expectDocument, latest.Spec.PolicyDocument != nil)
+			c := ackcondition.FirstOfType(&resource{latest}, svcapitypes.ConditionTypePolicyDrifted)
+			if !tt.expectCondition {
+				assert.Nil(t, c)
+				return
+			}
+			require.NotNil(t, c)
+			assert.Equal(t, corev1.ConditionTrue, c.Status)
+			assert.Equal(t, ConditionReasonPolicyDrifted, *c.Reason)
+			assert.Contains(t, *c.Message, "unexpected")
+		})
+	}
+
+	// The condition is removed once the key policy matches again
+	desired := newTestKeyWithPolicy(aws.String(desiredDriftPolicy))
+	desired.ko.Spec.PolicyDriftMode = aws.String(PolicyDriftModeObserve)
+	latest := desired.ko.DeepCopy()
+	require.NoError(t, syncPolicyDrift(desired.ko, latest, desired.ko.Spec.Policy, aws.String(driftedPolicy)))
+	require.NotNil(t, ackcondition.FirstOfType(&resource{latest}, svcapitypes.ConditionTypePolicyDrifted))
+	require.NoError(t, syncPolicyDrift(desired.ko, latest, desired.ko.Spec.Policy, aws.String(desiredDriftPolicy)))
+	assert.Nil(t, ackcondition.FirstOfType(&resource{latest}, svcapitypes.ConditionTypePolicyDrifted))
+}
+
+func TestCustomUpdate_PolicyDriftModeAdopt(t *testing.T) {
+	desired := newTestKeyWithPolicy(aws.String(desiredDriftPolicy))
+	desired.ko.Spec.PolicyDriftMode = aws.String(PolicyDriftModeAdopt)
+	latest := newTestKeyWithPolicy(aws.String(driftedPolicy))
+	latest.ko.Spec.PolicyDriftMode = aws.String(PolicyDriftModeAdopt)
+	delta := newResourceDelta(desired, latest)
+	require.True(t, delta.DifferentAt("Spec.Policy"))
+
+	fake := newFakeSDKAPI()
+	rm := newFakeResourceManager(fake)
+	updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+	require.NoError(t, err)
+	assert.False(t, fake.Called("PutKeyPolicy"))
+	assert.Equal(t, driftedPolicy, *updated.ko.Spec.Policy)
+}
@@ -15,19 +15,59 @@ package key
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
 )
 
 var (
 	// PolicyName is the only allowed value for KMS Key's PolicyName
 	// https://boto3.amazonaws.com/v1/documentation/api/latest/reference/services/kms.html#KMS.Client.put_key_policy
 	PolicyName = "default"
+	// ConditionReasonUnexpectedKeyPolicies is the reason of the ACK.Advisory
+	// condition recorded when the KMS key has key policies other than the
+	// default key policy
+	ConditionReasonUnexpectedKeyPolicies = "UnexpectedKeyPolicies"
 )
 
+// defaultKeyPolicy is the default key policy KMS attaches to keys created
+// without a key policy, see
+// https://docs.aws.amazon.com/kms/latest/developerguide/key-policy-default.html
+const defaultKeyPolicy = `{
+  "Version" : "2012-10-17",
+  "Id" : "key-default-1",
+  "Statement" : [ {
+    "Sid" : "Enable IAM User Permissions",
+    "Effect" : "Allow",
+    "Principal" : {
+      "AWS" : "arn:${Partition}:iam::${AccountId}:root"
+    },
+    "Action" : "kms:*",
+    "Resource" : "*"
+  } ]
+}`
+
+// hasDesiredPolicy returns true if the resource specifies a key policy, as
+// Spec.Policy, Spec.PolicyDocument or key policy fragments in
+// Spec.PolicyRefs.
+func hasDesiredPolicy(ko *svcapitypes.Key) bool {
+	return ko.Spec.PolicyDocument != nil ||
+		(ko.Spec.Policy != nil && *ko.Spec.Policy != "") ||
+		len(ko.Spec.PolicyRefs) > 0
+}
+
 // updatePolicy peforms the PutKeyPolicy operation after reading the Policy
-// and BypassPolicyLockoutSafetyCheck from resource spec
+// (or PolicyDocument), the key policy fragments and
+// BypassPolicyLockoutSafetyCheck from resource spec
 func (rm *resourceManager) updatePolicy(ctx context.Context, r *resource) (err error) {
 	rlog := ackrtlog.FromContext(ctx)
 	exit := rlog.Trace("rm.updatePolicy")
@@ -35,10 +75,64 @@ func (rm *resourceManager) updatePolicy(ctx context.Context, r *resource) (err e
 		exit(err)
 	}()
 
+	policy, err := rm.resolvedPolicy(ctx, r.ko)
+	if err != nil {
+		return err
+	}
+	if err = rm.putPolicy(ctx, r, policy); err != nil {
+		return err
+	}
+	r.ko.Status.PolicyApplied = aws.Bool(true)
+	return nil
+}
+
+// restoreDefaultPolicy puts the default key policy back on a key whose key
+// policy is no longer specified when Spec.RestoreDefaultPolicy is true, unless
+// the latest key policy is already the default one. Only a key policy applied
+// from the resource, as recorded by Status.PolicyApplied, is replaced: keys
+// that never specified one, such as adopted ones, keep theirs.
+func (rm *resourceManager) restoreDefaultPolicy(
+	ctx context.Context,
+	r *resource,
+	latest *resource,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.restoreDefaultPolicy")
+	defer func() {
+		exit(err)
+	}()
+
+	if r.ko.Spec.RestoreDefaultPolicy == nil || !*r.ko.Spec.RestoreDefaultPolicy {
+		return nil
+	}
+	if latest.ko.Status.PolicyApplied == nil || !*latest.ko.Status.PolicyApplied {
+		return nil
+	}
+	replacer, _ := rm.policyPlaceholderReplacer(latest.ko)
+	policy := replacer.Replace(defaultKeyPolicy)
+	if latest.ko.Spec.Policy != nil {
+		if equal, err := ackcompare.IAMPolicyDocumentEqual(policy, *latest.ko.Spec.Policy); err == nil && equal {
+			r.ko.Status.PolicyApplied = nil
+			return nil
+		}
+	}
+	if err = rm.putPolicy(ctx, r, &policy); err != nil {
+		return err
+	}
+	r.ko.Status.PolicyApplied = nil
+	return nil
+}
+
+// putPolicy performs the PutKeyPolicy API call with the supplied key policy,
+// once it is verified not to lock the controller out of the key.
+func (rm *resourceManager) putPolicy(ctx context.Context, r *resource, policy *string) (err error) {
+	if err = rm.checkPolicyLockout(ctx, r, policy); err != nil {
+		return err
+	}
 	input := &svcsdk.PutKeyPolicyInput{
 		BypassPolicyLockoutSafetyCheck: r.ko.Spec.BypassPolicyLockoutSafetyCheck != nil && *r.ko.Spec.BypassPolicyLockoutSafetyCheck,
 		KeyId:                          r.ko.Status.KeyID,
-		Policy:                         r.ko.Spec.Policy,
+		Policy:                         policy,
 		PolicyName:                     &PolicyName,
 	}
 
@@ -65,3 +159,72 @@ func (rm *resourceManager) getPolicy(ctx context.Context, r *resource) (policy *
 	}
 	return resp.Policy, nil
 }
+
+// listPolicyNames performs the ListKeyPolicies API call and returns the names
+// of the key policies of the KMS key
+func (rm *resourceManager) listPolicyNames(ctx context.Context, r *resource) (names []*string, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.listPolicyNames")
+	defer func() {
+		exit(err)
+	}()
+	var truncated = true
+	var marker *string
+	names = []*string{}
+	for truncated {
+		input := &svcsdk.ListKeyPoliciesInput{
+			KeyId:  r.ko.Status.KeyID,
+			Marker: marker,
+		}
+		resp, err := rm.sdkapi.ListKeyPolicies(ctx, input)
+		rm.metrics.RecordAPICall("GET", "ListKeyPolicies", err)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.PolicyNames {
+			names = append(names, &resp.PolicyNames[i])
+		}
+		truncated = resp.Truncated
+		marker = resp.NextMarker
+	}
+	return names, nil
+}
+
+// unexpectedPolicyNames returns the names of the key policies of the KMS key
+// other than the default key policy, which is the only one managed.
+func unexpectedPolicyNames(ko *svcapitypes.Key) []string {
+	names := []string{}
+	for _, name := range ko.Status.PolicyNames {
+		if name != nil && *name != PolicyName {
+			names = append(names, *name)
+		}
+	}
+	return names
+}
+
+// setUnexpectedPoliciesAdvisory sets the ACK.Advisory condition listing the
+// key policies of the KMS key other than the default key policy, or removes
+// it when there are none.
+func setUnexpectedPoliciesAdvisory(ctx context.Context, r *resource) {
+	names := unexpectedPolicyNames(r.ko)
+	if len(names) > 0 {
+		msg := fmt.Sprintf(
+			"the KMS key has key policies other than %q that are not managed: %s",
+			PolicyName, strings.Join(names, ", "),
+		)
+		ackrtlog.FromContext(ctx).Info("unexpected key policies", "policyNames", names)
+		ackcondition.SetAdvisory(r, corev1.ConditionTrue, &msg, &ConditionReasonUnexpectedKeyPolicies)
+		return
+	}
+	advisory := ackcondition.AdvisoryWithReason(r, ConditionReasonUnexpectedKeyPolicies)
+	if advisory == nil {
+		return
+	}
+	conditions := []*ackv1alpha1.Condition{}
+	for _, c := range r.Conditions() {
+		if c != advisory {
+			conditions = append(conditions, c)
+		}
+	}
+	r.ReplaceConditions(conditions)
+}
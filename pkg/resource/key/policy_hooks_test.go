@@ -0,0 +1,155 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+func TestCustomUpdate_RestoreDefaultPolicy(t *testing.T) {
+	// The default key policy as returned by GetKeyPolicy
+	observedDefault := `{"Version":"2012-10-17","Id":"key-default-1","Statement":[{"Sid":"Enable IAM User Permissions","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"}]}`
+	newLatest := func(policy string) *resource {
+		r := newTestKeyWithPolicy(aws.String(policy))
+		r.ko.Status.PolicyApplied = aws.Bool(true)
+		r.ko.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{
+			OwnerAccountID: (*ackv1alpha1.AWSAccountID)(aws.String("111122223333")),
+			Partition:      (*ackv1alpha1.AWSPartition)(aws.String("aws")),
+		}
+		return r
+	}
+	tests := []struct {
+		name          string
+		restore       *bool
+		latest        *resource
+		expectRestore bool
+	}{
+		{
+			name:          "custom key policy removed",
+			latest:        newLatest(desiredDriftPolicy),
+			expectRestore: false,
+		},
+		{
+			name:          "custom key policy removed with restoring enabled",
+			restore:       aws.Bool(true),
+			latest:        newLatest(desiredDriftPolicy),
+			expectRestore: true,
+		},
+		{
+			name:          "custom key policy removed with restoring disabled",
+			restore:       aws.Bool(false),
+			latest:        newLatest(desiredDriftPolicy),
+			expectRestore: false,
+		},
+		{
+			name:          "default key policy in place",
+			restore:       aws.Bool(true),
+			latest:        newLatest(observedDefault),
+			expectRestore: false,
+		},
+		{
+			name:    "key policy never applied from the resource",
+			restore: aws.Bool(true),
+			latest: func() *resource {
+				r := newLatest(desiredDriftPolicy)
+				r.ko.Status.PolicyApplied = nil
+				return r
+			}(),
+			expectRestore: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(nil)
+			desired.ko.Spec.RestoreDefaultPolicy = tt.restore
+			delta := newResourceDelta(desired, tt.latest)
+			require.True(t, delta.DifferentAt("Spec.Policy"))
+
+			fake := newFakeSDKAPI()
+			rm := newFakeResourceManager(fake)
+			updated, err := rm.customUpdate(context.TODO(), desired, tt.latest, delta)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectRestore, fake.Called("PutKeyPolicy"))
+			if !tt.expectRestore {
+				return
+			}
+			assert.Nil(t, updated.ko.Status.PolicyApplied)
+			input := fake.Inputs["PutKeyPolicy"].(*svcsdk.PutKeyPolicyInput)
+			assert.JSONEq(t, observedDefault, *input.Policy)
+			assert.Equal(t, PolicyName, *input.PolicyName)
+		})
+	}
+}
+
+func TestCustomUpdate_AdoptedKeyPolicy(t *testing.T) {
+	fake := newFakeSDKAPI()
+	fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateEnabled)
+	fake.Outputs["GetKeyPolicy"] = &svcsdk.GetKeyPolicyOutput{Policy: aws.String(desiredDriftPolicy)}
+	rm := newFakeResourceManager(fake)
+
+	// An adopted Key specifies no key policy
+	desired := &resource{&svcapitypes.Key{}}
+	require.NoError(t, desired.PopulateResourceFromAnnotation(map[string]string{"keyID": "1234abcd-12ab-34cd-56ef-1234567890ab"}))
+	latest, err := rm.sdkFind(context.TODO(), desired)
+	require.NoError(t, err)
+	require.Equal(t, desiredDriftPolicy, *latest.ko.Spec.Policy)
+
+	delta := newResourceDelta(desired, latest)
+	require.True(t, delta.DifferentAt("Spec.Policy"))
+	_, err = rm.customUpdate(context.TODO(), desired, latest, delta)
+	require.NoError(t, err)
+	assert.False(t, fake.Called("PutKeyPolicy"))
+}
+
+func TestListPolicyNames(t *testing.T) {
+	fake := newFakeSDKAPI()
+	fake.Outputs["ListKeyPolicies"] = &svcsdk.ListKeyPoliciesOutput{
+		PolicyNames: []string{"default", "legacy"},
+	}
+	rm := newFakeResourceManager(fake)
+	r := newTestKey(nil)
+
+	names, err := rm.listPolicyNames(context.TODO(), r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "legacy"}, aws.ToStringSlice(names))
+	input := fake.Inputs["ListKeyPolicies"].(*svcsdk.ListKeyPoliciesInput)
+	assert.Equal(t, r.ko.Status.KeyID, input.KeyId)
+}
+
+func TestSetUnexpectedPoliciesAdvisory(t *testing.T) {
+	r := newTestKey(nil)
+	r.ko.Status.PolicyNames = aws.StringSlice([]string{"default", "legacy"})
+	setUnexpectedPoliciesAdvisory(context.TODO(), r)
+	advisory := ackcondition.AdvisoryWithReason(r, ConditionReasonUnexpectedKeyPolicies)
+	require.NotNil(t, advisory)
+	assert.Contains(t, *advisory.Message, "legacy")
+
+	// The condition is removed once only the default key policy is left
+	r.ko.Status.PolicyNames = aws.StringSlice([]string{"default"})
+	setUnexpectedPoliciesAdvisory(context.TODO(), r)
+	assert.Nil(t, ackcondition.AdvisoryWithReason(r, ConditionReasonUnexpectedKeyPolicies))
+	assert.Empty(t, r.ko.Status.Conditions)
+}
@@ -0,0 +1,244 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// lockoutAction is the action the controller must retain on a key to keep
+// managing its key policy.
+const lockoutAction = "kms:PutKeyPolicy"
+
+// callerIdentities caches the callerIdentity of each resourceManager. The
+// resource managers are kept by their factory for the lifetime of the
+// controller, and the principal each of them calls KMS as never changes.
+var callerIdentities sync.Map
+
+// callerIdentity is the principal the controller calls KMS as.
+type callerIdentity struct {
+	// accountID is the account of the principal
+	accountID string
+	// partition is the partition of the principal
+	partition string
+	// arn is the ARN of the principal, which is an STS assumed-role session
+	// ARN when the controller runs with a role
+	arn string
+	// roleName is the name of the role of an assumed-role session, or "" if
+	// the principal is not one
+	roleName string
+}
+
+// lockoutStatement is the part of a key policy statement that decides whether
+// it applies to a call of kms:PutKeyPolicy by the controller.
+type lockoutStatement struct {
+	Effect       string          `json:"Effect"`
+	Principal    principalMap    `json:"Principal"`
+	NotPrincipal principalMap    `json:"NotPrincipal"`
+	Action       stringList      `json:"Action"`
+	NotAction    stringList      `json:"NotAction"`
+	Condition    json.RawMessage `json:"Condition"`
+}
+
+// checkPolicyLockout returns a terminal error if the supplied key policy would
+// lock the controller out of the key, that is if it does not unconditionally
+// allow the caller identity of the controller to call kms:PutKeyPolicy. The
+// check is skipped when there is no key policy, as KMS then applies the
+// default key policy, or when Spec.BypassPolicyLockoutSafetyCheck is true.
+func (rm *resourceManager) checkPolicyLockout(
+	ctx context.Context,
+	r *resource,
+	policy *string,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.checkPolicyLockout")
+	defer func() {
+		exit(err)
+	}()
+
+	if policy == nil || *policy == "" {
+		return nil
+	}
+	if r.ko.Spec.BypassPolicyLockoutSafetyCheck != nil && *r.ko.Spec.BypassPolicyLockoutSafetyCheck {
+		return nil
+	}
+	caller, err := rm.getCallerIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	allowed, err := policyAllowsPutKeyPolicy(*policy, caller)
+	if err != nil {
+		return ackerr.NewTerminalError(err)
+	}
+	if !allowed {
+		return ackerr.NewTerminalError(fmt.Errorf(
+			"key policy does not unconditionally allow %s to call %s, the controller might no "+
+				"longer be able to manage the key; set "+
+				"bypassPolicyLockoutSafetyCheck to true to apply it anyway",
+			caller.arn, lockoutAction,
+		))
+	}
+	return nil
+}
+
+// getCallerIdentity returns the principal the controller calls KMS as. It is
+// looked up with the GetCallerIdentity API call the first time only.
+func (rm *resourceManager) getCallerIdentity(ctx context.Context) (caller *callerIdentity, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.getCallerIdentity")
+	defer func() {
+		exit(err)
+	}()
+
+	if cached, ok := callerIdentities.Load(rm); ok {
+		return cached.(*callerIdentity), nil
+	}
+	resp, err := sts.NewFromConfig(rm.clientcfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	rm.metrics.RecordAPICall("GET", "GetCallerIdentity", err)
+	if err != nil {
+		return nil, err
+	}
+	caller = &callerIdentity{
+		accountID: aws.ToString(resp.Account),
+		partition: string(rm.awsPartition),
+		arn:       aws.ToString(resp.Arn),
+	}
+	if caller.accountID == "" {
+		caller.accountID = string(rm.awsAccountID)
+	}
+	if parsed, err := arn.Parse(caller.arn); err == nil {
+		caller.partition = parsed.Partition
+		// arn:aws:sts::111122223333:assumed-role/<role name>/<session name>
+		parts := strings.Split(parsed.Resource, "/")
+		if parsed.Service == "sts" && len(parts) == 3 && parts[0] == "assumed-role" {
+			caller.roleName = parts[1]
+		}
+	}
+	callerIdentities.Store(rm, caller)
+	return caller, nil
+}
+
+// policyAllowsPutKeyPolicy returns true if the JSON key policy allows the
+// caller to call kms:PutKeyPolicy. Conditions cannot be evaluated ahead of the
+// call, so only Allow statements without conditions are taken into account,
+// while a matching Deny statement, even a conditional one, overrides any Allow
+// statement.
+func policyAllowsPutKeyPolicy(policy string, caller *callerIdentity) (bool, error) {
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return false, fmt.Errorf("cannot parse key policy: %w", err)
+	}
+	statements, err := rawStatements(doc["Statement"])
+	if err != nil {
+		return false, fmt.Errorf("cannot parse key policy: %w", err)
+	}
+	allowed := false
+	for _, raw := range statements {
+		var s lockoutStatement
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return false, fmt.Errorf("cannot parse key policy: %w", err)
+		}
+		if !s.appliesTo(caller) {
+			continue
+		}
+		switch s.Effect {
+		case "Deny":
+			return false, nil
+		case "Allow":
+			allowed = true
+		}
+	}
+	return allowed, nil
+}
+
+// appliesTo returns true if the statement covers a call of kms:PutKeyPolicy
+// by the caller. An Allow statement with conditions never applies, as they
+// may not hold, and a Deny statement with conditions always does, as they may.
+func (s *lockoutStatement) appliesTo(caller *callerIdentity) bool {
+	condition := strings.TrimSpace(string(s.Condition))
+	if condition != "" && condition != "null" && condition != "{}" && s.Effect != "Deny" {
+		return false
+	}
+	switch {
+	case s.Principal != nil:
+		if !caller.matchesAny(s.Principal[principalAWS]) {
+			return false
+		}
+	case s.NotPrincipal != nil:
+		if caller.matchesAny(s.NotPrincipal[principalAWS]) {
+			return false
+		}
+	default:
+		return false
+	}
+	switch {
+	case s.Action != nil:
+		return matchesAction(s.Action)
+	case s.NotAction != nil:
+		return !matchesAction(s.NotAction)
+	}
+	return false
+}
+
+// matchesAny returns true if any of the supplied AWS principals designates
+// the caller.
+func (c *callerIdentity) matchesAny(principals []string) bool {
+	for _, p := range principals {
+		if c.matches(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if the AWS principal designates the caller, either as
+// itself, through its role, or through the account it belongs to.
+func (c *callerIdentity) matches(principal string) bool {
+	switch principal {
+	case "*", c.accountID, c.arn, fmt.Sprintf("arn:%s:iam::%s:root", c.partition, c.accountID):
+		return true
+	}
+	if c.roleName == "" {
+		return false
+	}
+	parsed, err := arn.Parse(principal)
+	if err != nil || parsed.Service != "iam" || parsed.AccountID != c.accountID {
+		return false
+	}
+	// Role ARNs may include a path, which the session ARN does not carry
+	return strings.HasPrefix(parsed.Resource, "role/") &&
+		parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:] == c.roleName
+}
+
+// matchesAction returns true if any of the supplied actions, which may use
+// wildcards, matches kms:PutKeyPolicy.
+func matchesAction(actions []string) bool {
+	for _, action := range actions {
+		if ok, _ := path.Match(strings.ToLower(action), strings.ToLower(lockoutAction)); ok {
+			return true
+		}
+	}
+	return false
+}
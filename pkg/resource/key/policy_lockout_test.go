@@ -0,0 +1,179 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyAllowsPutKeyPolicy(t *testing.T) {
+	caller := &callerIdentity{
+		accountID: "111122223333",
+		partition: "aws",
+		arn:       "arn:aws:sts::111122223333:assumed-role/ack-kms-controller/session",
+		roleName:  "ack-kms-controller",
+	}
+	tests := []struct {
+		name          string
+		statements    string
+		expectAllowed bool
+	}{
+		{
+			name:          "account root",
+			statements:    `{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"}`,
+			expectAllowed: true,
+		},
+		{
+			name:          "account ID",
+			statements:    `{"Effect":"Allow","Principal":{"AWS":"111122223333"},"Action":"*","Resource":"*"}`,
+			expectAllowed: true,
+		},
+		{
+			name:          "role with a path",
+			statements:    `[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::111122223333:role/app","arn:aws:iam::111122223333:role/ack/ack-kms-controller"]},"Action":["kms:Describe*","kms:Put*"],"Resource":"*"}]`,
+			expectAllowed: true,
+		},
+		{
+			name:          "other role",
+			statements:    `[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":"kms:*","Resource":"*"}]`,
+			expectAllowed: false,
+		},
+		{
+			name:          "role of another account",
+			statements:    `[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::444455556666:role/ack-kms-controller"},"Action":"kms:*","Resource":"*"}]`,
+			expectAllowed: false,
+		},
+		{
+			name:          "other actions",
+			statements:    `[{"Effect":"Allow","Principal":"*","Action":["kms:Encrypt","kms:PutKeyPolicyX"],"Resource":"*"}]`,
+			expectAllowed: false,
+		},
+		{
+			name:          "conditional allow",
+			statements:    `[{"Effect":"Allow","Principal":"*","Action":"kms:*","Resource":"*","Condition":{"StringEquals":{"kms:CallerAccount":"111122223333"}}}]`,
+			expectAllowed: false,
+		},
+		{
+			name: "explicit deny",
+			statements: `[
+				{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"},
+				{"Effect":"Deny","NotPrincipal":{"AWS":"arn:aws:iam::111122223333:role/admin"},"Action":"kms:PutKeyPolicy","Resource":"*"}
+			]`,
+			expectAllowed: false,
+		},
+		{
+			name: "conditional deny",
+			statements: `[
+				{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"},
+				{"Effect":"Deny","Principal":"*","Action":"kms:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":"false"}}}
+			]`,
+			expectAllowed: false,
+		},
+		{
+			name: "conditional deny of other actions",
+			statements: `[
+				{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"},
+				{"Effect":"Deny","Principal":"*","Action":"kms:Decrypt","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":"false"}}}
+			]`,
+			expectAllowed: true,
+		},
+		{
+			name:          "not action",
+			statements:    `[{"Effect":"Allow","Principal":{"AWS":"arn:aws:sts::111122223333:assumed-role/ack-kms-controller/session"},"NotAction":"kms:ScheduleKeyDeletion","Resource":"*"}]`,
+			expectAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := `{"Version":"2012-10-17","Statement":` + tt.statements + `}`
+			allowed, err := policyAllowsPutKeyPolicy(policy, caller)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectAllowed, allowed)
+		})
+	}
+
+	_, err := policyAllowsPutKeyPolicy(`not json`, caller)
+	assert.Error(t, err)
+}
+
+func TestCheckPolicyLockout(t *testing.T) {
+	lockout := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":"kms:*","Resource":"*"}]}`
+	var terminal *ackerr.TerminalError
+
+	fake := newFakeSDKAPI()
+	rm := newFakeResourceManager(fake)
+	r := newTestKey(nil)
+	err := rm.checkPolicyLockout(context.TODO(), r, &lockout)
+	require.ErrorAs(t, err, &terminal)
+	assert.Contains(t, err.Error(), "ack-kms-controller")
+
+	// The check is skipped when bypassed explicitly or without a key policy
+	fake = newFakeSDKAPI()
+	rm = newFakeResourceManager(fake)
+	r.ko.Spec.BypassPolicyLockoutSafetyCheck = aws.Bool(true)
+	require.NoError(t, rm.checkPolicyLockout(context.TODO(), r, &lockout))
+	require.NoError(t, rm.checkPolicyLockout(context.TODO(), newTestKey(nil), nil))
+	assert.False(t, fake.Called("GetCallerIdentity"))
+
+	// A conditional Deny statement may apply to the controller, so it needs
+	// the check to be bypassed
+	conditionalDeny := `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"},
+		{"Effect":"Deny","Principal":"*","Action":"kms:PutKeyPolicy","Resource":"*","Condition":{"StringNotEquals":{"aws:PrincipalTag/team":"security"}}}
+	]}`
+	err = rm.checkPolicyLockout(context.TODO(), newTestKey(nil), &conditionalDeny)
+	require.ErrorAs(t, err, &terminal)
+	require.NoError(t, rm.checkPolicyLockout(context.TODO(), r, &conditionalDeny))
+
+	// The caller identity is looked up once per resource manager
+	require.ErrorAs(t, rm.checkPolicyLockout(context.TODO(), newTestKey(nil), &lockout), &terminal)
+	calls := 0
+	for _, c := range fake.Calls {
+		if c == "GetCallerIdentity" {
+			calls++
+		}
+	}
+	assert.Equal(t, 1, calls)
+
+	// Failing to get the caller identity is not terminal
+	fake = newFakeSDKAPI()
+	rm = newFakeResourceManager(fake)
+	fake.Errors["GetCallerIdentity"] = errors.New("boom")
+	err = rm.checkPolicyLockout(context.TODO(), newTestKey(nil), &lockout)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &terminal))
+}
+
+func TestUpdatePolicy_Lockout(t *testing.T) {
+	fake := newFakeSDKAPI()
+	rm := newFakeResourceManager(fake)
+	r := newTestKeyWithPolicyDocument(testPolicyDocument())
+	r.ko.Spec.PolicyDocument.Statements = r.ko.Spec.PolicyDocument.Statements[1:]
+
+	err := rm.updatePolicy(context.TODO(), r)
+	var terminal *ackerr.TerminalError
+	require.ErrorAs(t, err, &terminal)
+	assert.False(t, fake.Called("PutKeyPolicy"))
+
+	// The account root delegates to the IAM policies of the controller's role
+	require.NoError(t, rm.updatePolicy(context.TODO(), newTestKeyWithPolicyDocument(testPolicyDocument())))
+	assert.True(t, fake.Called("PutKeyPolicy"))
+}
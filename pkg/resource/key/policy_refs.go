@@ -0,0 +1,268 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+const (
+	// DefaultPolicyFragmentKey is the ConfigMap entry holding a key policy
+	// fragment when the reference does not name one.
+	DefaultPolicyFragmentKey = "policy"
+
+	placeholderAccountID = "${AccountId}"
+	placeholderRegion    = "${Region}"
+	placeholderPartition = "${Partition}"
+	placeholderKeyARN    = "${KeyArn}"
+)
+
+// policyFragmentReader reads the ConfigMaps holding the key policy fragments
+// referenced by Keys. It is set by SetupPolicyRefsWatcher.
+var policyFragmentReader client.Reader
+
+// requeueWaitForPolicyRefs lets a key created with policy fragments be
+// reconciled again, so that fragments using its ARN are merged into its key
+// policy.
+var requeueWaitForPolicyRefs = ackrequeue.NeededAfter(
+	errors.New("key created, requeuing to merge the key policy fragments"),
+	ackrequeue.DefaultRequeueAfterDuration,
+)
+
+// resolvedPolicy returns the JSON key policy of the resource, authored in
+// Spec.Policy or Spec.PolicyDocument, with the key policy fragments
+// referenced by Spec.PolicyRefs merged into it. Fragments using the key ARN
+// are skipped until the key exists. Returns nil if the resource specifies
+// neither a key policy nor policy fragments.
+func (rm *resourceManager) resolvedPolicy(
+	ctx context.Context,
+	ko *svcapitypes.Key,
+) (*string, error) {
+	policy, err := desiredPolicy(ko)
+	if err != nil || len(ko.Spec.PolicyRefs) == 0 {
+		return policy, err
+	}
+	if policyFragmentReader == nil {
+		return nil, errors.New("key policy fragments cannot be read, no reader is set up")
+	}
+	replacer, keyExists := rm.policyPlaceholderReplacer(ko)
+	fragments := make([]string, 0, len(ko.Spec.PolicyRefs))
+	for _, ref := range ko.Spec.PolicyRefs {
+		if ref == nil || ref.Name == nil || *ref.Name == "" {
+			return nil, fmt.Errorf("provided resource reference is nil or empty: PolicyRefs")
+		}
+		fragment, err := getPolicyFragment(ctx, policyFragmentReader, ko.Namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		if !keyExists && strings.Contains(fragment, placeholderKeyARN) {
+			// The key ARN is only known once the key is created
+			continue
+		}
+		fragments = append(fragments, replacer.Replace(fragment))
+	}
+	merged, err := mergePolicyFragments(policy, fragments)
+	if err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// policyPlaceholderReplacer returns a replacer substituting the placeholders
+// of key policy fragments with the values of the key, and whether the key
+// exists. The key ARN placeholder is only substituted once the key exists.
+func (rm *resourceManager) policyPlaceholderReplacer(ko *svcapitypes.Key) (*strings.Replacer, bool) {
+	metadata := ko.Status.ACKResourceMetadata
+	if metadata == nil {
+		metadata = &ackv1alpha1.ResourceMetadata{}
+	}
+	accountID := string(rm.awsAccountID)
+	if metadata.OwnerAccountID != nil {
+		accountID = string(*metadata.OwnerAccountID)
+	}
+	region := string(rm.awsRegion)
+	if metadata.Region != nil {
+		region = string(*metadata.Region)
+	}
+	partition := string(rm.awsPartition)
+	if metadata.Partition != nil {
+		partition = string(*metadata.Partition)
+	}
+	replacements := []string{
+		placeholderAccountID, accountID,
+		placeholderRegion, region,
+		placeholderPartition, partition,
+	}
+	if metadata.ARN == nil {
+		return strings.NewReplacer(replacements...), false
+	}
+	replacements = append(replacements, placeholderKeyARN, string(*metadata.ARN))
+	return strings.NewReplacer(replacements...), true
+}
+
+// getPolicyFragment returns the key policy fragment held in the ConfigMap
+// entry referenced by ref.
+func getPolicyFragment(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ref *svcapitypes.KeyPolicyFragmentReference,
+) (string, error) {
+	key := DefaultPolicyFragmentKey
+	if ref.Key != nil && *ref.Key != "" {
+		key = *ref.Key
+	}
+	cm := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      *ref.Name,
+	}
+	if err := apiReader.Get(ctx, namespacedName, cm); err != nil {
+		return "", err
+	}
+	fragment, ok := cm.Data[key]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s has no key policy fragment in %q", namespacedName, key)
+	}
+	return fragment, nil
+}
+
+// mergePolicyFragments adds the statements of the key policy fragments to
+// those of the supplied key policy, which may be nil. Statements whose Sid is
+// already in the key policy, and statements already in it, are skipped.
+func mergePolicyFragments(policy *string, fragments []string) (string, error) {
+	doc := map[string]json.RawMessage{}
+	if policy != nil && *policy != "" {
+		if err := json.Unmarshal([]byte(*policy), &doc); err != nil {
+			return "", fmt.Errorf("cannot parse key policy: %w", err)
+		}
+	}
+	if _, ok := doc["Version"]; !ok {
+		doc["Version"] = json.RawMessage(`"` + PolicyVersion + `"`)
+	}
+	statements, err := rawStatements(doc["Statement"])
+	if err != nil {
+		return "", fmt.Errorf("cannot parse key policy: %w", err)
+	}
+
+	sids := map[string]bool{}
+	seen := map[string]bool{}
+	for _, statement := range statements {
+		if sid := statementSid(statement); sid != "" {
+			sids[sid] = true
+		}
+		seen[compactJSON(statement)] = true
+	}
+	for i, fragment := range fragments {
+		fragmentStatements, err := policyFragmentStatements(fragment)
+		if err != nil {
+			return "", fmt.Errorf("cannot parse key policy fragment %d: %w", i, err)
+		}
+		for _, statement := range fragmentStatements {
+			sid := statementSid(statement)
+			if (sid != "" && sids[sid]) || seen[compactJSON(statement)] {
+				continue
+			}
+			if sid != "" {
+				sids[sid] = true
+			}
+			seen[compactJSON(statement)] = true
+			statements = append(statements, statement)
+		}
+	}
+	doc["Statement"] = append(append([]byte("["), bytes.Join(toBytes(statements), []byte(","))...), ']')
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// policyFragmentStatements returns the statements of a key policy fragment,
+// which is either a key policy document or a list of statements.
+func policyFragmentStatements(fragment string) ([]json.RawMessage, error) {
+	b := bytes.TrimSpace([]byte(fragment))
+	if len(b) > 0 && b[0] == '[' {
+		return rawStatements(b)
+	}
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	statement, ok := doc["Statement"]
+	if !ok {
+		return nil, errors.New("no Statement in key policy document")
+	}
+	return rawStatements(statement)
+}
+
+// rawStatements returns the statements of the Statement element of a key
+// policy, which is either a single statement or a list of statements.
+func rawStatements(b json.RawMessage) ([]json.RawMessage, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || bytes.Equal(b, []byte("null")) {
+		return []json.RawMessage{}, nil
+	}
+	if b[0] != '[' {
+		return []json.RawMessage{b}, nil
+	}
+	statements := []json.RawMessage{}
+	if err := json.Unmarshal(b, &statements); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// toBytes converts raw JSON messages to byte slices.
+func toBytes(messages []json.RawMessage) [][]byte {
+	b := make([][]byte, len(messages))
+	for i, m := range messages {
+		b[i] = m
+	}
+	return b
+}
+
+// statementSid returns the Sid of a statement, or "" if it has none.
+func statementSid(statement json.RawMessage) string {
+	var s struct {
+		Sid string `json:"Sid"`
+	}
+	_ = json.Unmarshal(statement, &s)
+	return s.Sid
+}
+
+// compactJSON returns the supplied JSON without insignificant whitespace.
+func compactJSON(b json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return string(b)
+	}
+	return buf.String()
+}
@@ -0,0 +1,270 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+const (
+	rootFragment = `{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Sid": "EnableRootAccess",
+			"Effect": "Allow",
+			"Principal": {"AWS": "arn:${Partition}:iam::${AccountId}:root"},
+			"Action": "kms:*",
+			"Resource": "*"
+		}]
+	}`
+	breakGlassFragment = `[{
+		"Sid": "BreakGlass",
+		"Effect": "Allow",
+		"Principal": {"AWS": "arn:${Partition}:iam::${AccountId}:role/break-glass"},
+		"Action": "kms:*",
+		"Resource": "${KeyArn}"
+	}]`
+)
+
+func newFakeReader(t *testing.T, objs ...client.Object) client.Reader {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, svcapitypes.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newFragmentConfigMap(name, key, fragment string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Data:       map[string]string{key: fragment},
+	}
+}
+
+// statementSids returns the Sids of the statements of a JSON key policy.
+func statementSids(t *testing.T, policy string) []string {
+	var doc struct {
+		Statement []struct {
+			Sid string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(policy), &doc))
+	sids := []string{}
+	for _, s := range doc.Statement {
+		sids = append(sids, s.Sid)
+	}
+	return sids
+}
+
+func TestMergePolicyFragments(t *testing.T) {
+	base := `{"Version":"2012-10-17","Id":"custom","Statement":[{"Sid":"EnableRootAccess","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:root"},"Action":"kms:*","Resource":"*"}]}`
+	appUse := `{"Statement":{"Sid":"AllowUse","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":["kms:Encrypt","kms:Decrypt"],"Resource":"*"}}`
+	noSid := `[{"Effect":"Deny","Principal":"*","Action":"kms:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":"false"}}}]`
+
+	policy, err := mergePolicyFragments(&base, []string{rootFragment, appUse, noSid, noSid})
+	require.NoError(t, err)
+	// The statement of the key policy wins over the fragment with the same Sid
+	// and identical statements are only added once
+	assert.Equal(t, []string{"EnableRootAccess", "AllowUse", ""}, statementSids(t, policy))
+	assert.Contains(t, policy, `"Id":"custom"`)
+	assert.Contains(t, policy, `arn:aws:iam::111122223333:root`)
+
+	// Fragments alone make up the key policy when there is no other
+	policy, err = mergePolicyFragments(nil, []string{appUse})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Version": "2012-10-17",
+		"Statement": [{"Sid":"AllowUse","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":["kms:Encrypt","kms:Decrypt"],"Resource":"*"}]
+	}`, policy)
+
+	// Merging is idempotent
+	again, err := mergePolicyFragments(&policy, []string{appUse})
+	require.NoError(t, err)
+	assert.JSONEq(t, policy, again)
+
+	_, err = mergePolicyFragments(nil, []string{`{"Version":"2012-10-17"}`})
+	assert.Error(t, err)
+	_, err = mergePolicyFragments(nil, []string{`not json`})
+	assert.Error(t, err)
+}
+
+// setPolicyFragmentReader makes the key policy fragments readable from the
+// supplied ConfigMaps for the duration of the test.
+func setPolicyFragmentReader(t *testing.T, objs ...client.Object) {
+	previous := policyFragmentReader
+	policyFragmentReader = newFakeReader(t, objs...)
+	t.Cleanup(func() { policyFragmentReader = previous })
+}
+
+func TestResolvedPolicy(t *testing.T) {
+	setPolicyFragmentReader(t,
+		newFragmentConfigMap("root", DefaultPolicyFragmentKey, rootFragment),
+		newFragmentConfigMap("break-glass", "statements", breakGlassFragment),
+	)
+	refs := []*svcapitypes.KeyPolicyFragmentReference{
+		{Name: aws.String("root")},
+		{Name: aws.String("break-glass"), Key: aws.String("statements")},
+	}
+	rm := newFakeResourceManager(newFakeSDKAPI())
+
+	// Before the key is created, fragments using its ARN are skipped
+	r := newTestKeyWithPolicyDocument(nil)
+	r.ko.Namespace = "default"
+	r.ko.Spec.PolicyRefs = refs
+	policy, err := rm.resolvedPolicy(context.TODO(), r.ko)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, []string{"EnableRootAccess"}, statementSids(t, *policy))
+	assert.Contains(t, *policy, "arn:aws:iam::111122223333:root")
+
+	// Once it exists, they are merged with the placeholders replaced
+	arn := ackv1alpha1.AWSResourceName("arn:aws-cn:kms:cn-north-1:444455556666:key/1234abcd")
+	r = newTestKeyWithPolicyDocument(testPolicyDocument())
+	r.ko.Namespace = "default"
+	r.ko.Spec.PolicyRefs = refs
+	r.ko.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{
+		ARN:            &arn,
+		OwnerAccountID: (*ackv1alpha1.AWSAccountID)(aws.String("444455556666")),
+		Region:         (*ackv1alpha1.AWSRegion)(aws.String("cn-north-1")),
+		Partition:      (*ackv1alpha1.AWSPartition)(aws.String("aws-cn")),
+	}
+	policy, err = rm.resolvedPolicy(context.TODO(), r.ko)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, []string{"EnableRootAccess", "AllowUse", "BreakGlass"}, statementSids(t, *policy))
+	assert.Contains(t, *policy, "arn:aws-cn:iam::444455556666:role/break-glass")
+	assert.Contains(t, *policy, string(arn))
+	assert.NotContains(t, *policy, "${")
+
+	// The authored key policy is left as is
+	assert.Nil(t, r.ko.Spec.Policy)
+	assert.Equal(t, testPolicyDocument(), r.ko.Spec.PolicyDocument)
+	assert.Empty(t, r.ko.Annotations)
+
+	// Missing ConfigMaps fail the resolution
+	r = newTestKey(nil)
+	r.ko.Namespace = "default"
+	r.ko.Spec.PolicyRefs = []*svcapitypes.KeyPolicyFragmentReference{{Name: aws.String("missing")}}
+	_, err = rm.resolvedPolicy(context.TODO(), r.ko)
+	assert.Error(t, err)
+
+	// Keys without policy fragments have their authored key policy
+	base := `{"Version":"2012-10-17","Statement":[]}`
+	r = newTestKeyWithPolicy(aws.String(base))
+	policy, err = rm.resolvedPolicy(context.TODO(), r.ko)
+	require.NoError(t, err)
+	assert.Equal(t, &base, policy)
+}
+
+func TestSyncPolicyDrift_PolicyRefs(t *testing.T) {
+	setPolicyFragmentReader(t,
+		newFragmentConfigMap("root", DefaultPolicyFragmentKey, rootFragment),
+	)
+	rm := newFakeResourceManager(newFakeSDKAPI())
+	base := `{"Version":"2012-10-17","Statement":[{"Sid":"Base","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111122223333:role/app"},"Action":"kms:Decrypt","Resource":"*"}]}`
+	desired := newTestKeyWithPolicy(aws.String(base))
+	desired.ko.Namespace = "default"
+	desired.ko.Spec.PolicyRefs = []*svcapitypes.KeyPolicyFragmentReference{{Name: aws.String("root")}}
+	merged, err := rm.resolvedPolicy(context.TODO(), desired.ko)
+	require.NoError(t, err)
+
+	// The merged key policy matches the desired one
+	latest := &resource{desired.ko.DeepCopy()}
+	require.NoError(t, syncPolicyDrift(desired.ko, latest.ko, merged, merged))
+	assert.False(t, newResourceDelta(desired, latest).DifferentAt("Spec"))
+
+	// The key policy is the authored one, without the fragments
+	latest = &resource{desired.ko.DeepCopy()}
+	require.NoError(t, syncPolicyDrift(desired.ko, latest.ko, merged, aws.String(base)))
+	delta := newResourceDelta(desired, latest)
+	assert.True(t, delta.DifferentAt("Spec.PolicyRefs"))
+	assert.False(t, delta.DifferentAt("Spec.Policy"))
+
+	fake := newFakeSDKAPI()
+	desired.ko.Status.KeyID = aws.String("1234abcd-12ab-34cd-56ef-1234567890ab")
+	desired.ko.Spec.BypassPolicyLockoutSafetyCheck = aws.Bool(true)
+	_, err = newFakeResourceManager(fake).customUpdate(context.TODO(), desired, latest, delta)
+	require.NoError(t, err)
+	require.True(t, fake.Called("PutKeyPolicy"))
+	input := fake.Inputs["PutKeyPolicy"].(*svcsdk.PutKeyPolicyInput)
+	assert.Equal(t, []string{"Base", "EnableRootAccess"}, statementSids(t, *input.Policy))
+}
+
+func TestPolicyRefsWatcher(t *testing.T) {
+	newKey := func(name, namespace string, refs ...string) *svcapitypes.Key {
+		ko := &svcapitypes.Key{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		for _, ref := range refs {
+			ko.Spec.PolicyRefs = append(ko.Spec.PolicyRefs, &svcapitypes.KeyPolicyFragmentReference{Name: aws.String(ref)})
+		}
+		return ko
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, svcapitypes.AddToScheme(scheme))
+	root := newFragmentConfigMap("root", DefaultPolicyFragmentKey, rootFragment)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithIndex(
+		&svcapitypes.Key{}, policyRefsIndexField, policyRefNames,
+	).WithObjects(
+		root,
+		newKey("uses-root", "default", "app", "root"),
+		newKey("uses-app", "default", "app"),
+		newKey("other-namespace", "other", "root"),
+	).Build()
+	w := &policyRefsWatcher{kc: kc}
+
+	// Only the Keys referencing the ConfigMap are enqueued
+	requests := w.referencingKeys(context.TODO(), root)
+	require.Len(t, requests, 1)
+	assert.Equal(t, client.ObjectKey{Namespace: "default", Name: "uses-root"}, requests[0].NamespacedName)
+	assert.Empty(t, w.referencingKeys(context.TODO(), newFragmentConfigMap("unrelated", DefaultPolicyFragmentKey, rootFragment)))
+
+	revision := func() string {
+		ko := &svcapitypes.Key{}
+		require.NoError(t, kc.Get(context.TODO(), requests[0].NamespacedName, ko))
+		return ko.Annotations[svcapitypes.AnnotationPolicyRefsRevision]
+	}
+
+	// The Key is annotated with the revision of its key policy fragments,
+	// the missing "app" ConfigMap having none
+	_, err := w.Reconcile(context.TODO(), requests[0])
+	require.NoError(t, err)
+	require.NoError(t, kc.Get(context.TODO(), client.ObjectKeyFromObject(root), root))
+	assert.Equal(t, ","+root.ResourceVersion, revision())
+
+	// A change of a key policy fragment changes the revision
+	root.Data[DefaultPolicyFragmentKey] = breakGlassFragment
+	require.NoError(t, kc.Update(context.TODO(), root))
+	_, err = w.Reconcile(context.TODO(), requests[0])
+	require.NoError(t, err)
+	assert.Equal(t, ","+root.ResourceVersion, revision())
+
+	// Deleted Keys are ignored
+	_, err = w.Reconcile(context.TODO(), ctrlrt.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "deleted"}})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,169 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+// policyRefsIndexField is the name of the field index of Keys by the names of
+// the ConfigMaps they reference in Spec.PolicyRefs.
+const policyRefsIndexField = "spec.policyRefs.name"
+
+// SetupPolicyRefsWatcher registers a controller with the manager that
+// updates the AnnotationPolicyRefsRevision annotation of the Keys referencing
+// a ConfigMap in Spec.PolicyRefs whenever the ConfigMap changes. The Keys are
+// found through a field index of Keys by the ConfigMaps they reference, so
+// ConfigMaps no Key references update nothing. The controller never
+// reconciles Keys itself: the Key controller merges the changed key policy
+// fragments the next time it reconciles the Key, right away when it watches
+// annotation changes (see the IgnoreFieldDrift feature gate of the ACK
+// runtime), on the next resync of the Key otherwise. The key policy
+// fragments themselves are read through the API reader of the manager. It
+// must be called after the service controller is bound to the manager.
+func SetupPolicyRefsWatcher(mgr ctrlrt.Manager, sc acktypes.ServiceController) error {
+	policyFragmentReader = mgr.GetAPIReader()
+	for _, r := range sc.GetReconcilers() {
+		gvk := r.GroupVersionKind()
+		if gvk.Group != GroupKind.Group || gvk.Kind != GroupKind.Kind {
+			continue
+		}
+		if err := mgr.GetFieldIndexer().IndexField(
+			context.Background(), &svcapitypes.Key{}, policyRefsIndexField, policyRefNames,
+		); err != nil {
+			return err
+		}
+		w := &policyRefsWatcher{
+			kc: mgr.GetClient(),
+		}
+		return ctrlrt.NewControllerManagedBy(
+			mgr,
+		).Named(
+			"key-policy-refs",
+		).WatchesMetadata(
+			&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(w.referencingKeys),
+		).Complete(w)
+	}
+	return errors.New("no reconciler found for Key resources")
+}
+
+// policyRefsWatcher annotates the Keys referencing a ConfigMap holding a key
+// policy fragment with the revision of their key policy fragments.
+type policyRefsWatcher struct {
+	kc client.Client
+}
+
+// referencingKeys returns a request for each Key in the namespace of the
+// ConfigMap that references it in Spec.PolicyRefs.
+func (w *policyRefsWatcher) referencingKeys(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	keys := &svcapitypes.KeyList{}
+	if err := w.kc.List(
+		ctx, keys,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{policyRefsIndexField: obj.GetName()},
+	); err != nil {
+		ctrlrt.LoggerFrom(ctx).Error(err, "unable to list Keys referencing ConfigMap", "configMap", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(keys.Items))
+	for _, ko := range keys.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: ko.Namespace,
+				Name:      ko.Name,
+			},
+		})
+	}
+	return requests
+}
+
+// Reconcile sets the AnnotationPolicyRefsRevision annotation of a Key
+// referencing a ConfigMap that changed.
+func (w *policyRefsWatcher) Reconcile(
+	ctx context.Context,
+	req ctrlrt.Request,
+) (ctrlrt.Result, error) {
+	ko := &svcapitypes.Key{}
+	if err := w.kc.Get(ctx, req.NamespacedName, ko); err != nil {
+		return ctrlrt.Result{}, client.IgnoreNotFound(err)
+	}
+	if !ko.DeletionTimestamp.IsZero() {
+		return ctrlrt.Result{}, nil
+	}
+	revision, err := w.policyRefsRevision(ctx, ko)
+	if err != nil {
+		return ctrlrt.Result{}, err
+	}
+	if current, ok := ko.GetAnnotations()[svcapitypes.AnnotationPolicyRefsRevision]; ok && current == revision {
+		return ctrlrt.Result{}, nil
+	}
+	patch := client.MergeFrom(ko.DeepCopy())
+	if ko.Annotations == nil {
+		ko.Annotations = map[string]string{}
+	}
+	ko.Annotations[svcapitypes.AnnotationPolicyRefsRevision] = revision
+	return ctrlrt.Result{}, w.kc.Patch(ctx, ko, patch)
+}
+
+// policyRefsRevision returns the resource versions of the ConfigMaps the Key
+// references in Spec.PolicyRefs, in order and separated by commas. A missing
+// ConfigMap has an empty resource version.
+func (w *policyRefsWatcher) policyRefsRevision(
+	ctx context.Context,
+	ko *svcapitypes.Key,
+) (string, error) {
+	versions := []string{}
+	for _, name := range policyRefNames(ko) {
+		cm := &metav1.PartialObjectMetadata{}
+		cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		err := w.kc.Get(ctx, types.NamespacedName{Namespace: ko.Namespace, Name: name}, cm)
+		if client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+		versions = append(versions, cm.ResourceVersion)
+	}
+	return strings.Join(versions, ","), nil
+}
+
+// policyRefNames returns the names of the ConfigMaps the Key references in
+// Spec.PolicyRefs.
+func policyRefNames(obj client.Object) []string {
+	ko, ok := obj.(*svcapitypes.Key)
+	if !ok {
+		return nil
+	}
+	names := []string{}
+	for _, ref := range ko.Spec.PolicyRefs {
+		if ref != nil && ref.Name != nil {
+			names = append(names, *ref.Name)
+		}
+	}
+	return names
+}
@@ -17,9 +17,15 @@ package key
 
 import (
 	"context"
+	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
 
 	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
@@ -32,6 +38,10 @@ import (
 func (rm *resourceManager) ClearResolvedReferences(res acktypes.AWSResource) acktypes.AWSResource {
 	ko := rm.concreteResource(res).ko.DeepCopy()
 
+	if ko.Spec.CustomKeyStoreRef != nil {
+		ko.Spec.CustomKeyStoreID = nil
+	}
+
 	return &resource{ko}
 }
 
@@ -47,11 +57,116 @@ func (rm *resourceManager) ResolveReferences(
 	apiReader client.Reader,
 	res acktypes.AWSResource,
 ) (acktypes.AWSResource, bool, error) {
-	return res, false, nil
+	ko := rm.concreteResource(res).ko
+
+	resourceHasReferences := false
+	err := validateReferenceFields(ko)
+	if fieldHasReferences, err := rm.resolveReferenceForCustomKeyStoreID(ctx, apiReader, ko); err != nil {
+		return &resource{ko}, (resourceHasReferences || fieldHasReferences), err
+	} else {
+		resourceHasReferences = resourceHasReferences || fieldHasReferences
+	}
+
+	return &resource{ko}, resourceHasReferences, err
 }
 
 // validateReferenceFields validates the reference field and corresponding
 // identifier field.
 func validateReferenceFields(ko *svcapitypes.Key) error {
+
+	if ko.Spec.CustomKeyStoreRef != nil && ko.Spec.CustomKeyStoreID != nil {
+		return ackerr.ResourceReferenceAndIDNotSupportedFor("CustomKeyStoreID", "CustomKeyStoreRef")
+	}
+	return nil
+}
+
+// resolveReferenceForCustomKeyStoreID reads the resource referenced
+// from CustomKeyStoreRef field and sets the CustomKeyStoreID
+// from referenced resource. Returns a boolean indicating whether a reference
+// contains references, or an error
+func (rm *resourceManager) resolveReferenceForCustomKeyStoreID(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.Key,
+) (hasReferences bool, err error) {
+	if ko.Spec.CustomKeyStoreRef != nil && ko.Spec.CustomKeyStoreRef.From != nil {
+		hasReferences = true
+		arr := ko.Spec.CustomKeyStoreRef.From
+		if arr.Name == nil || *arr.Name == "" {
+			return hasReferences, fmt.Errorf("provided resource reference is nil or empty: CustomKeyStoreRef")
+		}
+		namespace, err := ackrt.ResolveCrossNamespaceReference(
+			ctx,
+			rm.cfg.EnableCrossNamespace,
+			&ko.Status.Conditions,
+			ackrt.CrossNamespaceRefKindResource,
+			ko.ObjectMeta.GetNamespace(),
+			arr.Namespace,
+			*arr.Name,
+		)
+		if err != nil {
+			return hasReferences, err
+		}
+		obj := &svcapitypes.CustomKeyStore{}
+		if err := getReferencedResourceState_CustomKeyStore(ctx, apiReader, obj, *arr.Name, namespace); err != nil {
+			return hasReferences, err
+		}
+		ko.Spec.CustomKeyStoreID = (*string)(obj.Status.CustomKeyStoreID)
+	}
+
+	return hasReferences, nil
+}
+
+// getReferencedResourceState_CustomKeyStore looks up whether a referenced resource
+// exists and is in a ACK.ResourceSynced=True state. If the referenced resource does exist and is
+// in a Synced state, returns nil, otherwise returns `ackerr.ResourceReferenceTerminalFor` or
+// `ResourceReferenceNotSyncedFor` depending on if the resource is in a Terminal state.
+func getReferencedResourceState_CustomKeyStore(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.CustomKeyStore,
+	name string, // the Kubernetes name of the referenced resource
+	namespace string, // the Kubernetes namespace of the referenced resource
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	var refResourceTerminal bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			return ackerr.ResourceReferenceTerminalFor(
+				"CustomKeyStore",
+				namespace, name)
+		}
+	}
+	if refResourceTerminal {
+		return ackerr.ResourceReferenceTerminalFor(
+			"CustomKeyStore",
+			namespace, name)
+	}
+	var refResourceSynced bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeResourceSynced &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceSynced = true
+		}
+	}
+	if !refResourceSynced {
+		return ackerr.ResourceReferenceNotSyncedFor(
+			"CustomKeyStore",
+			namespace, name)
+	}
+	if obj.Status.CustomKeyStoreID == nil {
+		return ackerr.ResourceReferenceMissingTargetFieldFor(
+			"CustomKeyStore",
+			namespace, name,
+			"Status.CustomKeyStoreID")
+	}
 	return nil
 }
@@ -0,0 +1,176 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"context"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerrors "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+func TestSetIdentifiers(t *testing.T) {
+	keyARN := "arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	tests := []struct {
+		name          string
+		identifier    *ackv1alpha1.AWSIdentifiers
+		expectedKeyID string
+		expectErr     bool
+	}{
+		{
+			name:          "key ID",
+			identifier:    &ackv1alpha1.AWSIdentifiers{NameOrID: "1234abcd-12ab-34cd-56ef-1234567890ab"},
+			expectedKeyID: "1234abcd-12ab-34cd-56ef-1234567890ab",
+		},
+		{
+			name:          "alias name",
+			identifier:    &ackv1alpha1.AWSIdentifiers{NameOrID: "alias/my-key"},
+			expectedKeyID: "alias/my-key",
+		},
+		{
+			name:          "key ARN",
+			identifier:    &ackv1alpha1.AWSIdentifiers{NameOrID: keyARN},
+			expectedKeyID: keyARN,
+		},
+		{
+			name:          "alias ARN",
+			identifier:    &ackv1alpha1.AWSIdentifiers{NameOrID: "arn:aws:kms:us-west-2:111122223333:alias/my-key"},
+			expectedKeyID: "arn:aws:kms:us-west-2:111122223333:alias/my-key",
+		},
+		{
+			name:       "no identifier",
+			identifier: &ackv1alpha1.AWSIdentifiers{},
+			expectErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &resource{&svcapitypes.Key{}}
+			err := r.SetIdentifiers(tt.identifier)
+			if tt.expectErr {
+				assert.Equal(t, ackerrors.MissingNameIdentifier, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedKeyID, *r.ko.Status.KeyID)
+		})
+	}
+}
+
+func TestPopulateResourceFromAnnotation(t *testing.T) {
+	r := &resource{&svcapitypes.Key{}}
+	require.NoError(t, r.PopulateResourceFromAnnotation(map[string]string{"keyID": "alias/my-key"}))
+	assert.Equal(t, "alias/my-key", *r.ko.Status.KeyID)
+
+	aliasARN := "arn:aws:kms:us-west-2:111122223333:alias/my-key"
+	r = &resource{&svcapitypes.Key{}}
+	require.NoError(t, r.PopulateResourceFromAnnotation(map[string]string{"keyID": aliasARN}))
+	assert.Equal(t, aliasARN, *r.ko.Status.KeyID)
+
+	r = &resource{&svcapitypes.Key{}}
+	assert.Error(t, r.PopulateResourceFromAnnotation(map[string]string{}))
+}
+
+func TestSdkFind_AdoptByAlias(t *testing.T) {
+	fake := newFakeSDKAPI()
+	fake.Outputs["DescribeKey"] = describeKeyOutput(svcsdktypes.KeyStateEnabled)
+	rm := newFakeResourceManager(fake)
+
+	desired := &resource{&svcapitypes.Key{}}
+	require.NoError(t, desired.PopulateResourceFromAnnotation(map[string]string{"keyID": "alias/my-key"}))
+	latest, err := rm.sdkFind(context.TODO(), desired)
+	require.NoError(t, err)
+
+	// DescribeKey resolves the alias, every other call uses the key ID
+	describeInput := fake.Inputs["DescribeKey"].(*svcsdk.DescribeKeyInput)
+	assert.Equal(t, "alias/my-key", *describeInput.KeyId)
+	policyInput := fake.Inputs["GetKeyPolicy"].(*svcsdk.GetKeyPolicyInput)
+	assert.Equal(t, "1234abcd-12ab-34cd-56ef-1234567890ab", *policyInput.KeyId)
+
+	assert.Equal(t, "1234abcd-12ab-34cd-56ef-1234567890ab", *latest.ko.Status.KeyID)
+	require.NotNil(t, latest.ko.Status.ACKResourceMetadata.ARN)
+	assert.Equal(t,
+		aws.String("arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+		(*string)(latest.ko.Status.ACKResourceMetadata.ARN),
+	)
+}
+
+func TestCustomUpdate_Enabled(t *testing.T) {
+	tests := []struct {
+		name          string
+		desired       bool
+		latest        bool
+		expectedCalls []string
+	}{
+		{
+			// A disabled key rejects the other updates, so it is enabled
+			// first
+			name:    "key enabled",
+			desired: true,
+			latest:  false,
+			expectedCalls: []string{
+				"EnableKey", "UpdateKeyDescription", "GetKeyRotationStatus", "EnableKeyRotation",
+			},
+		},
+		{
+			// The key is only disabled once the other updates are made
+			name:    "key disabled",
+			desired: false,
+			latest:  true,
+			expectedCalls: []string{
+				"UpdateKeyDescription", "GetKeyRotationStatus", "EnableKeyRotation", "DisableKey",
+			},
+		},
+		{
+			name:    "key stays enabled",
+			desired: true,
+			latest:  true,
+			expectedCalls: []string{
+				"UpdateKeyDescription", "GetKeyRotationStatus", "EnableKeyRotation",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestKey(aws.String("new description"))
+			desired.ko.Spec.Enabled = aws.Bool(tt.desired)
+			desired.ko.Spec.EnableKeyRotation = aws.Bool(true)
+			latest := newTestKey(aws.String("old description"))
+			latest.ko.Spec.Enabled = aws.Bool(tt.latest)
+			latest.ko.Spec.EnableKeyRotation = aws.Bool(false)
+			latest.ko.Status.Enabled = aws.Bool(tt.latest)
+			keyState := svcsdktypes.KeyStateDisabled
+			if tt.latest {
+				keyState = svcsdktypes.KeyStateEnabled
+			}
+			latest.ko.Status.KeyState = aws.String(string(keyState))
+			delta := newResourceDelta(desired, latest)
+
+			fake := newFakeSDKAPI()
+			rm := newFakeResourceManager(fake)
+			updated, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, fake.Calls)
+			assert.Equal(t, tt.desired, *updated.ko.Status.Enabled)
+		})
+	}
+}
@@ -70,6 +70,7 @@ func (rm *resourceManager) sdkFind(
 		return nil, ackerr.NotFound
 	}
 
+	defer requeueOnThrottling(&err)
 	input, err := rm.newDescribeRequestPayload(r)
 	if err != nil {
 		return nil, err
@@ -144,6 +145,17 @@ func (rm *resourceManager) sdkFind(
 	} else {
 		ko.Status.ExpirationModel = nil
 	}
+	if resp.KeyMetadata.KeyAgreementAlgorithms != nil {
+		f10 := []*string{}
+		for _, f10iter := range resp.KeyMetadata.KeyAgreementAlgorithms {
+			var f10elem *string
+			f10elem = aws.String(string(f10iter))
+			f10 = append(f10, f10elem)
+		}
+		ko.Status.KeyAgreementAlgorithms = f10
+	} else {
+		ko.Status.KeyAgreementAlgorithms = nil
+	}
 	if resp.KeyMetadata.KeyId != nil {
 		ko.Status.KeyID = resp.KeyMetadata.KeyId
 	} else {
@@ -245,24 +257,84 @@ func (rm *resourceManager) sdkFind(
 	} else {
 		ko.Status.ValidTo = nil
 	}
+	if resp.KeyMetadata.XksKeyConfiguration != nil {
+		f22 := &svcapitypes.XksKeyConfigurationType{}
+		if resp.KeyMetadata.XksKeyConfiguration.Id != nil {
+			f22.ID = resp.KeyMetadata.XksKeyConfiguration.Id
+		}
+		ko.Status.XksKeyConfiguration = f22
+	} else {
+		ko.Status.XksKeyConfiguration = nil
+	}
 
 	rm.setStatusDefaults(ko)
+	// A key scheduled for deletion is gone as far as a resource being
+	// deleted is concerned. Any other resource still wants the key, its
+	// deletion is canceled by customUpdate.
+	if isKeyPendingDeletion(ko) && r.IsBeingDeleted() {
+		return &resource{ko}, ackerr.NotFound
+	}
+	if isKeyPendingImport(ko) && !r.IsBeingDeleted() {
+		err = rm.syncKeyMaterialImport(ctx, &resource{ko})
+		if err != nil {
+			return &resource{ko}, err
+		}
+	}
+	if keyMaterialExpires(ko) && !r.IsBeingDeleted() {
+		err = rm.syncKeyMaterialExpiry(ctx, &resource{ko})
+		if err != nil {
+			return &resource{ko}, err
+		}
+	}
+	setKeyUsableCondition(ko)
+	validateDeletePendingWindow(&resource{ko})
+	// A key waiting for its key material stays disabled until the material
+	// is imported, which enables it.
+	if !isKeyPendingImport(ko) {
+		ko.Spec.Enabled = aws.Bool(*ko.Status.Enabled)
+	} else if ko.Spec.Enabled == nil {
+		ko.Spec.Enabled = aws.Bool(true)
+	}
+	if ko.Spec.PrimaryRegion != nil {
+		ko.Spec.PrimaryRegion = primaryKeyRegion(ko)
+	}
 	policy, err := rm.getPolicy(ctx, &resource{ko})
 	if err != nil {
 		return &resource{ko}, err
 	}
-	ko.Spec.Policy = policy
+	mergedPolicy, err := rm.resolvedPolicy(ctx, ko)
+	if err != nil {
+		return &resource{ko}, err
+	}
+	if err = syncPolicyDrift(r.ko, ko, mergedPolicy, policy); err != nil {
+		return &resource{ko}, err
+	}
+	ko.Status.PolicyNames, err = rm.listPolicyNames(ctx, &resource{ko})
+	if err != nil {
+		return &resource{ko}, err
+	}
+	setUnexpectedPoliciesAdvisory(ctx, &resource{ko})
 	tags, err := rm.listTags(ctx, &resource{ko})
 	if err != nil {
 		return &resource{ko}, err
 	}
 	ko.Spec.Tags = fromACKTags(tags, nil)
+	// OnDemandRotationGeneration is not returned by KMS, the last generation
+	// the key was rotated for stands in for the observed value.
+	ko.Spec.OnDemandRotationGeneration = ko.Status.LastOnDemandRotationGeneration
 	keyRotationStatus, err := rm.getKeyRotationStatus(ctx, &resource{ko})
-	if err != nil || keyRotationStatus == nil {
+	if err != nil {
 		return &resource{ko}, err
 	}
-	enabled := keyRotationStatus.KeyRotationEnabled
-	ko.Spec.EnableKeyRotation = &enabled
+	// Asymmetric, HMAC and imported keys have no rotation status
+	if keyRotationStatus != nil {
+		setKeyRotationStatus(ko, keyRotationStatus)
+	}
+	recentRotations, err := rm.listRecentRotations(ctx, &resource{ko})
+	if err != nil {
+		return &resource{ko}, err
+	}
+	ko.Status.RecentRotations = recentRotations
 	return &resource{ko}, nil
 }
 
@@ -302,10 +374,18 @@ func (rm *resourceManager) sdkCreate(
 	defer func() {
 		exit(err)
 	}()
+	defer requeueOnThrottling(&err)
 	input, err := rm.newCreateRequestPayload(ctx, desired)
 	if err != nil {
 		return nil, err
 	}
+	input.Policy, err = rm.resolvedPolicy(ctx, desired.ko)
+	if err != nil {
+		return nil, err
+	}
+	if err = rm.checkPolicyLockout(ctx, desired, input.Policy); err != nil {
+		return nil, err
+	}
 
 	var resp *svcsdk.CreateKeyOutput
 	_ = resp
@@ -372,6 +452,17 @@ func (rm *resourceManager) sdkCreate(
 	} else {
 		ko.Status.ExpirationModel = nil
 	}
+	if resp.KeyMetadata.KeyAgreementAlgorithms != nil {
+		f10 := []*string{}
+		for _, f10iter := range resp.KeyMetadata.KeyAgreementAlgorithms {
+			var f10elem *string
+			f10elem = aws.String(string(f10iter))
+			f10 = append(f10, f10elem)
+		}
+		ko.Status.KeyAgreementAlgorithms = f10
+	} else {
+		ko.Status.KeyAgreementAlgorithms = nil
+	}
 	if resp.KeyMetadata.KeyId != nil {
 		ko.Status.KeyID = resp.KeyMetadata.KeyId
 	} else {
@@ -473,17 +564,47 @@ func (rm *resourceManager) sdkCreate(
 	} else {
 		ko.Status.ValidTo = nil
 	}
+	if resp.KeyMetadata.XksKeyConfiguration != nil {
+		f22 := &svcapitypes.XksKeyConfigurationType{}
+		if resp.KeyMetadata.XksKeyConfiguration.Id != nil {
+			f22.ID = resp.KeyMetadata.XksKeyConfiguration.Id
+		}
+		ko.Status.XksKeyConfiguration = f22
+	} else {
+		ko.Status.XksKeyConfiguration = nil
+	}
 
 	rm.setStatusDefaults(ko)
 	policy, err := rm.getPolicy(ctx, &resource{ko})
 	if err != nil {
 		return &resource{ko}, err
 	}
-	ko.Spec.Policy = policy
+	setPolicy(ko, policy)
+	if hasDesiredPolicy(desired.ko) {
+		ko.Status.PolicyApplied = aws.Bool(true)
+	}
 	err = rm.updateKeyRotation(ctx, &resource{ko})
 	if err != nil {
 		return &resource{ko}, err
 	}
+	// A freshly created key has nothing to rotate on demand yet
+	ko.Status.LastOnDemandRotationGeneration = ko.Spec.OnDemandRotationGeneration
+	if isKeyPendingImport(ko) {
+		// The key can only be disabled once its key material is imported
+		err = rm.syncKeyMaterialImport(ctx, &resource{ko})
+		if err != nil {
+			return &resource{ko}, err
+		}
+	} else if !isKeyEnabled(&resource{ko}) {
+		err = rm.updateKeyEnabled(ctx, &resource{ko})
+		if err != nil {
+			return &resource{ko}, err
+		}
+	}
+	setKeyUsableCondition(ko)
+	if len(ko.Spec.PolicyRefs) > 0 {
+		return &resource{ko}, requeueWaitForPolicyRefs
+	}
 	return &resource{ko}, nil
 }
 
@@ -533,6 +654,9 @@ func (rm *resourceManager) newCreateRequestPayload(
 		}
 		res.Tags = f8
 	}
+	if r.ko.Spec.XksKeyID != nil {
+		res.XksKeyId = r.ko.Spec.XksKeyID
+	}
 
 	return res, nil
 }
@@ -558,15 +682,24 @@ func (rm *resourceManager) sdkDelete(
 	defer func() {
 		exit(err)
 	}()
+	defer requeueOnThrottling(&err)
+	if isDeletionProtected(r) {
+		return r, errDeletionProtected
+	}
 	input, err := rm.newDeleteRequestPayload(r)
 	if err != nil {
 		return nil, err
 	}
-	input.PendingWindowInDays = aws.Int32(int32(GetDeletePendingWindowInDays(&r.ko.ObjectMeta)))
+	input.PendingWindowInDays = aws.Int32(int32(getDeletePendingWindowInDays(r)))
 	var resp *svcsdk.ScheduleKeyDeletionOutput
 	_ = resp
 	resp, err = rm.sdkapi.ScheduleKeyDeletion(ctx, input)
 	rm.metrics.RecordAPICall("DELETE", "ScheduleKeyDeletion", err)
+	if err == nil {
+		ko := r.ko.DeepCopy()
+		setKeyDeletionStatus(ko, resp)
+		return &resource{ko}, nil
+	}
 	return nil, err
 }
 
@@ -686,6 +819,24 @@ func (rm *resourceManager) updateConditions(
 // and if the exception indicates that it is a Terminal exception
 // 'Terminal' exception are specified in generator configuration
 func (rm *resourceManager) terminalAWSError(err error) bool {
-	// No terminal_errors specified for this resource in generator config
-	return false
+	if err == nil {
+		return false
+	}
+
+	var terminalErr smithy.APIError
+	if !errors.As(err, &terminalErr) {
+		return false
+	}
+	switch terminalErr.ErrorCode() {
+	case "InvalidArnException",
+		"MalformedPolicyDocumentException",
+		"TagException",
+		"UnsupportedOperationException",
+		"ValidationException",
+		"XksKeyAlreadyInUseException",
+		"XksKeyInvalidConfigurationException":
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,47 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/aws-controllers-k8s/kms-controller/pkg/internal/testutil"
+)
+
+// newFakeSDKAPI returns a fake KMS API that also answers the
+// GetCallerIdentity calls issued through a client built from its
+// configuration.
+func newFakeSDKAPI() *testutil.FakeSDKAPI {
+	f := testutil.NewFakeSDKAPI()
+	f.Outputs["GetCallerIdentity"] = &sts.GetCallerIdentityOutput{
+		Account: aws.String("111122223333"),
+		Arn:     aws.String("arn:aws:sts::111122223333:assumed-role/ack-kms-controller/session"),
+	}
+	return f
+}
+
+// newFakeResourceManager returns a resourceManager backed by the supplied
+// fake KMS and STS APIs.
+func newFakeResourceManager(f *testutil.FakeSDKAPI) *resourceManager {
+	return &resourceManager{
+		awsAccountID: "111122223333",
+		awsRegion:    "us-west-2",
+		awsPartition: "aws",
+		clientcfg:    f.NewClientConfig(),
+		metrics:      ackmetrics.NewMetrics("kms"),
+		sdkapi:       f.NewClient(),
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package key
+
+import (
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+)
+
+var (
+	// keyStateMessages explains, for every KeyState other than Enabled, why
+	// the KMS key cannot be used in cryptographic operations
+	keyStateMessages = map[svcsdktypes.KeyState]string{
+		svcsdktypes.KeyStateCreating:               "The KMS key is being created in its custom key store",
+		svcsdktypes.KeyStateDisabled:               "The KMS key is disabled",
+		svcsdktypes.KeyStatePendingDeletion:        "The KMS key is scheduled for deletion",
+		svcsdktypes.KeyStatePendingImport:          "The KMS key is waiting for its key material to be imported",
+		svcsdktypes.KeyStatePendingReplicaDeletion: "The KMS key is waiting for its replica keys to be deleted",
+		svcsdktypes.KeyStateUnavailable:            "The custom key store of the KMS key is disconnected",
+		svcsdktypes.KeyStateUpdating:               "The KMS key is being updated",
+	}
+)
+
+// isKeyStateTransitional returns true if the KMS key is in a state that
+// neither the controller nor the user can settle, e.g. while it is being
+// created in a custom key store. Such a key is not synced and rejects
+// updates. A key waiting for its key material is not transitional, it only
+// rejects some updates, see isKeyPendingImport.
+func isKeyStateTransitional(ko *svcapitypes.Key) bool {
+	if ko.Status.KeyState == nil {
+		return false
+	}
+	switch svcsdktypes.KeyState(*ko.Status.KeyState) {
+	case svcsdktypes.KeyStateEnabled,
+		svcsdktypes.KeyStateDisabled,
+		svcsdktypes.KeyStatePendingDeletion,
+		svcsdktypes.KeyStatePendingImport:
+		return false
+	}
+	return true
+}
+
+// requeueWaitWhileKeyTransitional returns a requeue error that lets the
+// controller back off until the KMS key leaves its transitional state.
+func requeueWaitWhileKeyTransitional(ko *svcapitypes.Key) *ackrequeue.RequeueNeeded {
+	return ackrequeue.Needed(
+		fmt.Errorf("KMS key is in state %q, cannot be updated", *ko.Status.KeyState),
+	)
+}
+
+// requeueWaitForKeyMaterial returns a requeue error that lets the controller
+// back off until the key material of the KMS key is imported and its key
+// rotation can be updated.
+func requeueWaitForKeyMaterial(ko *svcapitypes.Key) *ackrequeue.RequeueNeeded {
+	return ackrequeue.Needed(
+		fmt.Errorf("KMS key is in state %q, its key rotation cannot be updated", *ko.Status.KeyState),
+	)
+}
+
+// setKeyUsableCondition sets the KMS.KeyUsable condition of the resource from
+// its KeyState. The condition is True when the KMS key is enabled; otherwise
+// its reason is the KeyState and its message explains why the key cannot be
+// used yet.
+func setKeyUsableCondition(ko *svcapitypes.Key) {
+	if ko.Status.KeyState == nil {
+		return
+	}
+	keyState := *ko.Status.KeyState
+	status := corev1.ConditionTrue
+	var message *string
+	if svcsdktypes.KeyState(keyState) != svcsdktypes.KeyStateEnabled {
+		status = corev1.ConditionFalse
+		msg, ok := keyStateMessages[svcsdktypes.KeyState(keyState)]
+		if !ok {
+			msg = fmt.Sprintf("The KMS key is in state %s", keyState)
+		}
+		message = &msg
+	}
+
+	r := &resource{ko}
+	allConds := r.Conditions()
+	c := ackcondition.FirstOfType(r, svcapitypes.ConditionTypeKeyUsable)
+	if c == nil {
+		c = &ackv1alpha1.Condition{
+			Type: svcapitypes.ConditionTypeKeyUsable,
+		}
+		allConds = append(allConds, c)
+	}
+	if c.Status != status {
+		now := metav1.Now()
+		c.LastTransitionTime = &now
+	}
+	c.Status = status
+	c.Message = message
+	c.Reason = &keyState
+	r.ReplaceConditions(allConds)
+}
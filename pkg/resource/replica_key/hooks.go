@@ -0,0 +1,191 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package replica_key
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/kms-controller/pkg/resource/key"
+	kmsutil "github.com/aws-controllers-k8s/kms-controller/pkg/util"
+)
+
+var (
+	// requeueWaitWhileCreating is returned while KMS is still replicating the
+	// primary key, the replica key rejects updates until it is created.
+	requeueWaitWhileCreating = ackrequeue.NeededAfter(
+		fmt.Errorf("replica key is in %s state, cannot be modified", svcsdktypes.KeyStateCreating),
+		10*time.Second,
+	)
+)
+
+// primaryKeyResourceManager returns a copy of the resource manager whose KMS
+// client sends requests to the Region of the primary key in Spec.KeyID.
+// Everything else, including the Region stored in the resource metadata, is
+// left untouched.
+func (rm *resourceManager) primaryKeyResourceManager(
+	r *resource,
+) (*resourceManager, error) {
+	if r.ko.Spec.KeyID == nil {
+		return nil, ackerr.NewTerminalError(fmt.Errorf("spec.keyID is required"))
+	}
+	keyARN, err := arn.Parse(*r.ko.Spec.KeyID)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(fmt.Errorf(
+			"spec.keyID must be the key ARN of a multi-Region primary key, got %q",
+			*r.ko.Spec.KeyID,
+		))
+	}
+	if keyARN.Region == string(rm.awsRegion) {
+		return nil, ackerr.NewTerminalError(fmt.Errorf(
+			"the primary key %s is in the same Region as the replica key",
+			*r.ko.Spec.KeyID,
+		))
+	}
+	primaryRM := *rm
+	primaryRM.sdkapi = svcsdk.New(rm.sdkapi.Options(), func(o *svcsdk.Options) {
+		o.Region = keyARN.Region
+	})
+	return &primaryRM, nil
+}
+
+// setPrimaryKeyID fills in Spec.KeyID with the ARN of the primary key when it
+// is not set, which happens when an existing replica key is adopted.
+func setPrimaryKeyID(ko *svcapitypes.ReplicaKey) {
+	if ko.Spec.KeyID != nil || ko.Spec.KeyRef != nil {
+		return
+	}
+	mrc := ko.Status.MultiRegionConfiguration
+	if mrc != nil && mrc.PrimaryKey != nil {
+		ko.Spec.KeyID = mrc.PrimaryKey.ARN
+	}
+}
+
+// customUpdate updates the properties of the replica key that are not shared
+// with the other keys of the multi-Region key: its description, key policy
+// and tags.
+func (rm *resourceManager) customUpdate(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.customUpdate")
+	defer func() {
+		exit(err)
+	}()
+	defer requeueOnThrottling(&err)
+	if latest.ko.Status.KeyState != nil &&
+		*latest.ko.Status.KeyState == string(svcsdktypes.KeyStateCreating) {
+		return latest, requeueWaitWhileCreating
+	}
+
+	updatedRes := rm.concreteResource(desired.DeepCopy())
+	updatedRes.SetStatus(latest)
+
+	if delta.DifferentAt("Spec.Description") {
+		if updatedRes.ko.Spec.Description != nil {
+			if err = rm.updateDescription(ctx, updatedRes); err != nil {
+				return updatedRes, err
+			}
+		}
+	}
+	if delta.DifferentAt("Spec.Policy") {
+		if updatedRes.ko.Spec.Policy != nil && *updatedRes.ko.Spec.Policy != "" {
+			if err = rm.updatePolicy(ctx, updatedRes); err != nil {
+				return updatedRes, err
+			}
+		}
+	}
+	if delta.DifferentAt("Spec.Tags") {
+		err = rm.updateTags(ctx, updatedRes)
+		if err != nil {
+			return updatedRes, err
+		}
+	}
+	rm.setStatusDefaults(updatedRes.ko)
+	return updatedRes, nil
+}
+
+// updateDescription performs the UpdateKeyDescription API call using the
+// Spec.Description field of the resource
+func (rm *resourceManager) updateDescription(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateDescription")
+	defer func() {
+		exit(err)
+	}()
+	_, err = rm.sdkapi.UpdateKeyDescription(ctx, &svcsdk.UpdateKeyDescriptionInput{
+		Description: r.ko.Spec.Description,
+		KeyId:       r.ko.Status.KeyID,
+	})
+	rm.metrics.RecordAPICall("UPDATE", "UpdateKeyDescription", err)
+	return err
+}
+
+// getDeletePendingWindowInDays returns the pending window (in days) used when
+// scheduling the deletion of the replica key. As for Key resources, a valid
+// annotation on the object takes precedence over Spec.PendingWindowInDays,
+// which otherwise defaults to key.DefaultDeletePendingWindowInDays.
+func getDeletePendingWindowInDays(r *resource) int64 {
+	pendingWindow, ok, err := key.DeletePendingWindowFromAnnotation(&r.ko.ObjectMeta)
+	if ok && err == nil {
+		return pendingWindow
+	}
+	if r.ko.Spec.PendingWindowInDays != nil {
+		return *r.ko.Spec.PendingWindowInDays
+	}
+	return key.DefaultDeletePendingWindowInDays
+}
+
+// isKeyPendingDeletion returns true if the replica key is scheduled for
+// deletion.
+func isKeyPendingDeletion(ko *svcapitypes.ReplicaKey) bool {
+	return ko.Status.KeyState != nil &&
+		*ko.Status.KeyState == string(svcsdktypes.KeyStatePendingDeletion)
+}
+
+// setKeyDeletionStatus records the outcome of ScheduleKeyDeletion in the
+// status of the resource.
+func setKeyDeletionStatus(
+	ko *svcapitypes.ReplicaKey,
+	out *svcsdk.ScheduleKeyDeletionOutput,
+) {
+	if out.DeletionDate != nil {
+		ko.Status.DeletionDate = &metav1.Time{Time: *out.DeletionDate}
+	}
+	if out.KeyState != "" {
+		keyState := string(out.KeyState)
+		ko.Status.KeyState = &keyState
+	}
+}
+
+// requeueOnThrottling turns the KMS throttling error err points to into a
+// requeue error, see kmsutil.RequeueOnThrottling. The operations of the
+// resource manager defer it.
+func requeueOnThrottling(err *error) {
+	*err = kmsutil.RequeueOnThrottling(*err)
+}
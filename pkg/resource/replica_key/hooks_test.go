@@ -0,0 +1,284 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package replica_key
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/kms-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/kms-controller/pkg/internal/testutil"
+)
+
+const (
+	testKeyID         = "mrk-1234abcd12ab34cd56ef1234567890ab"
+	testPrimaryKeyARN = "arn:aws:kms:us-east-1:111122223333:key/" + testKeyID
+	testReplicaKeyARN = "arn:aws:kms:us-west-2:111122223333:key/" + testKeyID
+)
+
+func newReplicaKey() *resource {
+	return &resource{&svcapitypes.ReplicaKey{
+		Spec: svcapitypes.ReplicaKeySpec{
+			KeyID:       aws.String(testPrimaryKeyARN),
+			Description: aws.String("replica"),
+			Tags: []*svcapitypes.Tag{
+				{TagKey: aws.String("team"), TagValue: aws.String("kms")},
+			},
+		},
+	}}
+}
+
+func TestSdkCreate_ReplicateKey(t *testing.T) {
+	f := testutil.NewFakeSDKAPI()
+	f.Outputs["ReplicateKey"] = &svcsdk.ReplicateKeyOutput{
+		ReplicaKeyMetadata: &svcsdktypes.KeyMetadata{
+			Arn:      aws.String(testReplicaKeyARN),
+			KeyId:    aws.String(testKeyID),
+			KeyState: svcsdktypes.KeyStateCreating,
+			MultiRegionConfiguration: &svcsdktypes.MultiRegionConfiguration{
+				MultiRegionKeyType: svcsdktypes.MultiRegionKeyTypeReplica,
+				PrimaryKey: &svcsdktypes.MultiRegionKey{
+					Arn:    aws.String(testPrimaryKeyARN),
+					Region: aws.String("us-east-1"),
+				},
+			},
+		},
+		ReplicaPolicy: aws.String(`{"Version":"2012-10-17"}`),
+		ReplicaTags: []svcsdktypes.Tag{
+			{TagKey: aws.String("team"), TagValue: aws.String("kms")},
+		},
+	}
+	rm := newFakeResourceManager(f)
+
+	created, err := rm.sdkCreate(context.TODO(), newReplicaKey())
+	require.NoError(t, err)
+
+	assert.Equal(t, "us-east-1", f.Regions["ReplicateKey"])
+	input := f.Inputs["ReplicateKey"].(*svcsdk.ReplicateKeyInput)
+	assert.Equal(t, testPrimaryKeyARN, *input.KeyId)
+	assert.Equal(t, "us-west-2", *input.ReplicaRegion)
+	assert.Equal(t, "replica", *input.Description)
+
+	assert.Equal(t, testKeyID, *created.ko.Status.KeyID)
+	assert.Equal(t, testReplicaKeyARN, string(*created.ko.Status.ACKResourceMetadata.ARN))
+	assert.Equal(t, "us-west-2", string(*created.ko.Status.ACKResourceMetadata.Region))
+	assert.Equal(t, string(svcsdktypes.KeyStateCreating), *created.ko.Status.KeyState)
+	assert.Equal(t, `{"Version":"2012-10-17"}`, *created.ko.Spec.Policy)
+	assert.Equal(t, testPrimaryKeyARN, *created.ko.Status.MultiRegionConfiguration.PrimaryKey.ARN)
+}
+
+func TestSdkCreate_InvalidPrimaryKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		keyID string
+	}{
+		{
+			name:  "key ID instead of key ARN",
+			keyID: testKeyID,
+		},
+		{
+			name:  "primary key in the replica Region",
+			keyID: testReplicaKeyARN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			rm := newFakeResourceManager(f)
+			desired := newReplicaKey()
+			desired.ko.Spec.KeyID = aws.String(tt.keyID)
+
+			_, err := rm.sdkCreate(context.TODO(), desired)
+
+			var termErr *ackerr.TerminalError
+			assert.True(t, errors.As(err, &termErr))
+			assert.False(t, f.Called("ReplicateKey"))
+		})
+	}
+}
+
+func TestSdkFind(t *testing.T) {
+	tests := []struct {
+		name           string
+		state          svcsdktypes.KeyState
+		beingDeleted   bool
+		expectNotFound bool
+		expectedKeyID  *string
+		keyIDFromSpec  bool
+	}{
+		{
+			name:          "adopted replica key gets its primary key",
+			state:         svcsdktypes.KeyStateEnabled,
+			expectedKeyID: aws.String(testPrimaryKeyARN),
+		},
+		{
+			name:           "pending deletion replica key being deleted is gone",
+			state:          svcsdktypes.KeyStatePendingDeletion,
+			beingDeleted:   true,
+			keyIDFromSpec:  true,
+			expectNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			f.Outputs["DescribeKey"] = &svcsdk.DescribeKeyOutput{
+				KeyMetadata: &svcsdktypes.KeyMetadata{
+					Arn:      aws.String(testReplicaKeyARN),
+					KeyId:    aws.String(testKeyID),
+					KeyState: tt.state,
+					MultiRegionConfiguration: &svcsdktypes.MultiRegionConfiguration{
+						PrimaryKey: &svcsdktypes.MultiRegionKey{
+							Arn: aws.String(testPrimaryKeyARN),
+						},
+					},
+				},
+			}
+			rm := newFakeResourceManager(f)
+			r := &resource{&svcapitypes.ReplicaKey{}}
+			r.ko.Status.KeyID = aws.String(testKeyID)
+			if tt.keyIDFromSpec {
+				r.ko.Spec.KeyID = aws.String(testPrimaryKeyARN)
+			}
+			if tt.beingDeleted {
+				now := metav1.Now()
+				r.ko.DeletionTimestamp = &now
+			}
+
+			latest, err := rm.sdkFind(context.TODO(), r)
+
+			if tt.expectNotFound {
+				assert.Equal(t, ackerr.NotFound, err)
+				assert.False(t, f.Called("GetKeyPolicy"))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "us-west-2", f.Regions["DescribeKey"])
+			assert.Equal(t, tt.expectedKeyID, latest.ko.Spec.KeyID)
+			assert.True(t, f.Called("GetKeyPolicy"))
+			assert.True(t, f.Called("ListResourceTags"))
+		})
+	}
+}
+
+func TestCustomUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         svcsdktypes.KeyState
+		expectRequeue bool
+		expectedCalls []string
+	}{
+		{
+			name:          "replica key being created is left alone",
+			state:         svcsdktypes.KeyStateCreating,
+			expectRequeue: true,
+		},
+		{
+			name:          "description and tags are updated",
+			state:         svcsdktypes.KeyStateEnabled,
+			expectedCalls: []string{"UpdateKeyDescription", "ListResourceTags", "TagResource"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			rm := newFakeResourceManager(f)
+			desired := newReplicaKey()
+			latest := newReplicaKey()
+			latest.ko.Spec.Description = aws.String("old")
+			latest.ko.Spec.Tags = nil
+			latest.ko.Status.KeyID = aws.String(testKeyID)
+			latest.ko.Status.KeyState = aws.String(string(tt.state))
+			delta := ackcompare.NewDelta()
+			delta.Add("Spec.Description", desired.ko.Spec.Description, latest.ko.Spec.Description)
+			delta.Add("Spec.Tags", desired.ko.Spec.Tags, latest.ko.Spec.Tags)
+
+			_, err := rm.customUpdate(context.TODO(), desired, latest, delta)
+
+			if tt.expectRequeue {
+				assert.Equal(t, requeueWaitWhileCreating, err)
+				assert.Empty(t, f.Calls)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, f.Calls)
+		})
+	}
+}
+
+func TestSdkDelete_PendingWindow(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotation     *string
+		specWindow     *int64
+		expectedWindow int32
+	}{
+		{
+			name:           "default",
+			expectedWindow: 7,
+		},
+		{
+			name:           "spec",
+			specWindow:     aws.Int64(20),
+			expectedWindow: 20,
+		},
+		{
+			name:           "annotation wins over spec",
+			annotation:     aws.String("10"),
+			specWindow:     aws.Int64(20),
+			expectedWindow: 10,
+		},
+		{
+			name:           "invalid annotation is ignored",
+			annotation:     aws.String("3"),
+			specWindow:     aws.Int64(20),
+			expectedWindow: 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testutil.NewFakeSDKAPI()
+			f.Outputs["ScheduleKeyDeletion"] = &svcsdk.ScheduleKeyDeletionOutput{
+				KeyState: svcsdktypes.KeyStatePendingDeletion,
+			}
+			rm := newFakeResourceManager(f)
+			r := newReplicaKey()
+			r.ko.Status.KeyID = aws.String(testKeyID)
+			r.ko.Spec.PendingWindowInDays = tt.specWindow
+			if tt.annotation != nil {
+				r.ko.Annotations = map[string]string{
+					svcapitypes.AnnotationDeletePendingWindow: *tt.annotation,
+				}
+			}
+
+			latest, err := rm.sdkDelete(context.TODO(), r)
+
+			require.NoError(t, err)
+			input := f.Inputs["ScheduleKeyDeletion"].(*svcsdk.ScheduleKeyDeletionInput)
+			assert.Equal(t, tt.expectedWindow, *input.PendingWindowInDays)
+			assert.Equal(t, "us-west-2", f.Regions["ScheduleKeyDeletion"])
+			assert.Equal(t, string(svcsdktypes.KeyStatePendingDeletion), *latest.ko.Status.KeyState)
+		})
+	}
+}
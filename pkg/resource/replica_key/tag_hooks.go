@@ -0,0 +1,46 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package replica_key
+
+import (
+	"context"
+
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+
+	kmsutil "github.com/aws-controllers-k8s/kms-controller/pkg/util"
+)
+
+// updateTags updates the tags of the KMS key to the Spec.Tags field of the
+// resource in the parameter
+func (rm *resourceManager) updateTags(ctx context.Context, r *resource) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.updateTags")
+	defer func() {
+		exit(err)
+	}()
+	desiredTags, _ := convertToOrderedACKTags(r.ko.Spec.Tags)
+	return kmsutil.SyncKeyTags(ctx, rm.sdkapi, rm.metrics, r.ko.Status.KeyID, desiredTags)
+}
+
+// listTags performs the ListResourceTags API call and returns the result in
+// form of acktags.Tags format
+func (rm *resourceManager) listTags(ctx context.Context, r *resource) (tags acktags.Tags, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.listTags")
+	defer func() {
+		exit(err)
+	}()
+	return kmsutil.ListKeyTags(ctx, rm.sdkapi, rm.metrics, r.ko.Status.KeyID)
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	smithy "github.com/aws/smithy-go"
+)
+
+const (
+	// ThrottlingRequeueAfter is the minimum time to wait before retrying a
+	// request that KMS throttled or could not process.
+	ThrottlingRequeueAfter = 30 * time.Second
+	// throttlingRequeueJitter is the maximum random time added to
+	// ThrottlingRequeueAfter, so that resources throttled together are not
+	// retried together.
+	throttlingRequeueJitter = 30 * time.Second
+)
+
+// throttlingErrorCodes are the codes of the KMS errors returned when a request
+// exceeds a KMS quota or KMS cannot process it for now. These errors are never
+// terminal.
+var throttlingErrorCodes = map[string]bool{
+	"KMSInternalException":   true,
+	"LimitExceededException": true,
+	"ThrottlingException":    true,
+}
+
+// IsThrottlingError returns true if the supplied error is a KMS error whose
+// code indicates that the request should be retried later.
+func IsThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && throttlingErrorCodes[apiErr.ErrorCode()]
+}
+
+// RequeueOnThrottling wraps a KMS throttling error in a requeue error so that
+// the resource is reconciled again after ThrottlingRequeueAfter plus a random
+// jitter, instead of being retried right away. Any other error is returned
+// unchanged.
+func RequeueOnThrottling(err error) error {
+	if !IsThrottlingError(err) {
+		return err
+	}
+	var requeueErr *ackrequeue.RequeueNeededAfter
+	if errors.As(err, &requeueErr) {
+		return err
+	}
+	jitter := time.Duration(rand.Int63n(int64(throttlingRequeueJitter)))
+	return ackrequeue.NeededAfter(err, ThrottlingRequeueAfter+jitter)
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	smithy "github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"not an AWS error", errors.New("boom"), false},
+		{"limit exceeded", &smithy.GenericAPIError{Code: "LimitExceededException"}, true},
+		{"internal", &smithy.GenericAPIError{Code: "KMSInternalException"}, true},
+		{"wrapped throttling", fmt.Errorf("op: %w", &smithy.GenericAPIError{Code: "ThrottlingException"}), true},
+		{"not found", &smithy.GenericAPIError{Code: "NotFoundException"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsThrottlingError(tt.err))
+		})
+	}
+}
+
+func TestRequeueOnThrottling(t *testing.T) {
+	apiErr := &smithy.GenericAPIError{Code: "LimitExceededException", Message: "slow down"}
+
+	err := RequeueOnThrottling(apiErr)
+	var requeueErr *ackrequeue.RequeueNeededAfter
+	require.True(t, errors.As(err, &requeueErr))
+	assert.GreaterOrEqual(t, requeueErr.Duration(), ThrottlingRequeueAfter)
+	assert.Less(t, requeueErr.Duration(), ThrottlingRequeueAfter+throttlingRequeueJitter)
+	assert.Equal(t, apiErr, requeueErr.Unwrap())
+
+	// already requeued errors are left alone
+	assert.Same(t, err, RequeueOnThrottling(err))
+
+	other := &smithy.GenericAPIError{Code: "ValidationException"}
+	assert.Equal(t, error(other), RequeueOnThrottling(other))
+	assert.Nil(t, RequeueOnThrottling(nil))
+}
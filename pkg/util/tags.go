@@ -0,0 +1,113 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"context"
+
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// ListKeyTags performs the ListResourceTags API call for the KMS key and
+// returns its tags in acktags.Tags format.
+func ListKeyTags(
+	ctx context.Context,
+	sdkapi *svcsdk.Client,
+	metrics *ackmetrics.Metrics,
+	keyID *string,
+) (acktags.Tags, error) {
+	var truncated = true
+	var marker *string
+	tags := acktags.NewTags()
+	for truncated {
+		listTagsInput := svcsdk.ListResourceTagsInput{
+			KeyId:  keyID,
+			Marker: marker,
+		}
+		resp, err := sdkapi.ListResourceTags(ctx, &listTagsInput)
+		metrics.RecordAPICall("GET", "ListResourceTags", err)
+		if err != nil {
+			return nil, err
+		}
+		truncated = resp.Truncated
+		marker = resp.NextMarker
+		for _, t := range resp.Tags {
+			tags[*t.TagKey] = *t.TagValue
+		}
+	}
+	return tags, nil
+}
+
+// SyncKeyTags updates the tags of the KMS key to desiredTags. The tags that
+// are not desired anymore are removed with the UntagResource API call, then
+// the desired tags are set with the TagResource API call.
+func SyncKeyTags(
+	ctx context.Context,
+	sdkapi *svcsdk.Client,
+	metrics *ackmetrics.Metrics,
+	keyID *string,
+	desiredTags acktags.Tags,
+) error {
+	latestTags, err := ListKeyTags(ctx, sdkapi, metrics, keyID)
+	if err != nil {
+		return err
+	}
+	// First remove the keys that are not present in desired state anymore
+	if tagKeysToRemove := removedTagKeys(desiredTags, latestTags); len(tagKeysToRemove) > 0 {
+		untagKeyInput := svcsdk.UntagResourceInput{
+			KeyId:   keyID,
+			TagKeys: tagKeysToRemove,
+		}
+		_, err = sdkapi.UntagResource(ctx, &untagKeyInput)
+		metrics.RecordAPICall("UPDATE", "UntagResource", err)
+		if err != nil {
+			return err
+		}
+	}
+	// Now tag the KMS Key with desired tags
+	if len(desiredTags) == 0 {
+		return nil
+	}
+	var svcTags []svcsdktypes.Tag
+	for k, v := range desiredTags {
+		kCopy := k
+		vCopy := v
+		svcTags = append(svcTags, svcsdktypes.Tag{
+			TagKey:   &kCopy,
+			TagValue: &vCopy,
+		})
+	}
+	tagKeyInput := svcsdk.TagResourceInput{
+		KeyId: keyID,
+		Tags:  svcTags,
+	}
+	_, err = sdkapi.TagResource(ctx, &tagKeyInput)
+	metrics.RecordAPICall("UPDATE", "TagResource", err)
+	return err
+}
+
+// removedTagKeys returns the tag keys that are present inside latestTags but
+// are not part of desiredTags
+func removedTagKeys(desiredTags acktags.Tags, latestTags acktags.Tags) []string {
+	var removedKeys []string
+	for k := range latestTags {
+		if _, found := desiredTags[k]; !found {
+			removedKeys = append(removedKeys, k)
+		}
+	}
+	return removedKeys
+}
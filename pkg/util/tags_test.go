@@ -0,0 +1,89 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/kms"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/kms-controller/pkg/internal/testutil"
+)
+
+func TestSyncKeyTags(t *testing.T) {
+	metrics := ackmetrics.NewMetrics("kms")
+	keyID := aws.String("1234abcd-12ab-34cd-56ef-1234567890ab")
+	tests := []struct {
+		name           string
+		desired        acktags.Tags
+		expectedCalls  []string
+		expectedRemove []string
+		expectedTags   map[string]string
+	}{
+		{
+			name:           "tags added, changed and removed",
+			desired:        acktags.Tags{"team": "security", "env": "prod"},
+			expectedCalls:  []string{"ListResourceTags", "UntagResource", "TagResource"},
+			expectedRemove: []string{"owner"},
+			expectedTags:   map[string]string{"team": "security", "env": "prod"},
+		},
+		{
+			name:          "tags unchanged",
+			desired:       acktags.Tags{"team": "platform", "owner": "alice"},
+			expectedCalls: []string{"ListResourceTags", "TagResource"},
+			expectedTags:  map[string]string{"team": "platform", "owner": "alice"},
+		},
+		{
+			name:           "all tags removed",
+			desired:        acktags.Tags{},
+			expectedCalls:  []string{"ListResourceTags", "UntagResource"},
+			expectedRemove: []string{"owner", "team"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testutil.NewFakeSDKAPI()
+			fake.Outputs["ListResourceTags"] = &svcsdk.ListResourceTagsOutput{
+				Tags: []svcsdktypes.Tag{
+					{TagKey: aws.String("team"), TagValue: aws.String("platform")},
+					{TagKey: aws.String("owner"), TagValue: aws.String("alice")},
+				},
+			}
+
+			err := SyncKeyTags(context.TODO(), fake.NewClient(), metrics, keyID, tt.desired)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, fake.Calls)
+			if tt.expectedRemove != nil {
+				removed := fake.Inputs["UntagResource"].(*svcsdk.UntagResourceInput).TagKeys
+				sort.Strings(removed)
+				assert.Equal(t, tt.expectedRemove, removed)
+			}
+			if tt.expectedTags != nil {
+				tags := map[string]string{}
+				for _, tag := range fake.Inputs["TagResource"].(*svcsdk.TagResourceInput).Tags {
+					tags[*tag.TagKey] = *tag.TagValue
+				}
+				assert.Equal(t, tt.expectedTags, tags)
+			}
+		})
+	}
+}
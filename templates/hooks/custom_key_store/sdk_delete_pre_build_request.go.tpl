@@ -0,0 +1,4 @@
+    defer requeueOnThrottling(&err)
+    if err = rm.disconnectBeforeDelete(ctx, r); err != nil {
+        return r, err
+    }
\ No newline at end of file
@@ -0,0 +1,2 @@
+    setXksProxyConfiguration(ko, resp.CustomKeyStores)
+    setConnected(r.ko, ko)
\ No newline at end of file
@@ -0,0 +1,7 @@
+    input.Policy, err = rm.resolvedPolicy(ctx, desired.ko)
+    if err != nil {
+        return nil, err
+    }
+    if err = rm.checkPolicyLockout(ctx, desired, input.Policy); err != nil {
+        return nil, err
+    }
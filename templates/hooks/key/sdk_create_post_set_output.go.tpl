@@ -2,8 +2,29 @@
     if err != nil {
         return &resource{ko}, err
     }
-    ko.Spec.Policy = policy
+    setPolicy(ko, policy)
+    if hasDesiredPolicy(desired.ko) {
+        ko.Status.PolicyApplied = aws.Bool(true)
+    }
     err = rm.updateKeyRotation(ctx, &resource{ko})
     if err != nil {
         return &resource{ko}, err
+    }
+    // A freshly created key has nothing to rotate on demand yet
+    ko.Status.LastOnDemandRotationGeneration = ko.Spec.OnDemandRotationGeneration
+    if isKeyPendingImport(ko) {
+        // The key can only be disabled once its key material is imported
+        err = rm.syncKeyMaterialImport(ctx, &resource{ko})
+        if err != nil {
+            return &resource{ko}, err
+        }
+    } else if !isKeyEnabled(&resource{ko}) {
+        err = rm.updateKeyEnabled(ctx, &resource{ko})
+        if err != nil {
+            return &resource{ko}, err
+        }
+    }
+    setKeyUsableCondition(ko)
+    if len(ko.Spec.PolicyRefs) > 0 {
+        return &resource{ko}, requeueWaitForPolicyRefs
     }
\ No newline at end of file
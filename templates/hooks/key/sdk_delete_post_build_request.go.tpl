@@ -1 +1 @@
-input.PendingWindowInDays = aws.Int32(int32(GetDeletePendingWindowInDays(&r.ko.ObjectMeta)))
\ No newline at end of file
+input.PendingWindowInDays = aws.Int32(int32(getDeletePendingWindowInDays(r)))
\ No newline at end of file
@@ -0,0 +1,4 @@
+    defer requeueOnThrottling(&err)
+    if isDeletionProtected(r) {
+        return r, errDeletionProtected
+    }
\ No newline at end of file
@@ -1,16 +1,67 @@
+    // A key scheduled for deletion is gone as far as a resource being
+    // deleted is concerned. Any other resource still wants the key, its
+    // deletion is canceled by customUpdate.
+    if isKeyPendingDeletion(ko) && r.IsBeingDeleted() {
+        return &resource{ko}, ackerr.NotFound
+    }
+    if isKeyPendingImport(ko) && !r.IsBeingDeleted() {
+        err = rm.syncKeyMaterialImport(ctx, &resource{ko})
+        if err != nil {
+            return &resource{ko}, err
+        }
+    }
+    if keyMaterialExpires(ko) && !r.IsBeingDeleted() {
+        err = rm.syncKeyMaterialExpiry(ctx, &resource{ko})
+        if err != nil {
+            return &resource{ko}, err
+        }
+    }
+    setKeyUsableCondition(ko)
+    validateDeletePendingWindow(&resource{ko})
+    // A key waiting for its key material stays disabled until the material
+    // is imported, which enables it.
+    if !isKeyPendingImport(ko) {
+        ko.Spec.Enabled = aws.Bool(*ko.Status.Enabled)
+    } else if ko.Spec.Enabled == nil {
+        ko.Spec.Enabled = aws.Bool(true)
+    }
+    if ko.Spec.PrimaryRegion != nil {
+        ko.Spec.PrimaryRegion = primaryKeyRegion(ko)
+    }
     policy, err := rm.getPolicy(ctx, &resource{ko})
     if err != nil {
         return &resource{ko}, err
     }
-    ko.Spec.Policy = policy
+    mergedPolicy, err := rm.resolvedPolicy(ctx, ko)
+    if err != nil {
+        return &resource{ko}, err
+    }
+    if err = syncPolicyDrift(r.ko, ko, mergedPolicy, policy); err != nil {
+        return &resource{ko}, err
+    }
+    ko.Status.PolicyNames, err = rm.listPolicyNames(ctx, &resource{ko})
+    if err != nil {
+        return &resource{ko}, err
+    }
+    setUnexpectedPoliciesAdvisory(ctx, &resource{ko})
     tags, err := rm.listTags(ctx, &resource{ko})
     if err != nil {
         return &resource{ko}, err
     }
     ko.Spec.Tags = fromACKTags(tags, nil)
+    // OnDemandRotationGeneration is not returned by KMS, the last generation
+    // the key was rotated for stands in for the observed value.
+    ko.Spec.OnDemandRotationGeneration = ko.Status.LastOnDemandRotationGeneration
     keyRotationStatus, err := rm.getKeyRotationStatus(ctx, &resource{ko})
-	if err != nil || keyRotationStatus == nil {
-		return &resource{ko}, err
-	}
-	enabled := keyRotationStatus.KeyRotationEnabled
-	ko.Spec.EnableKeyRotation = &enabled
\ No newline at end of file
+    if err != nil {
+        return &resource{ko}, err
+    }
+    // Asymmetric, HMAC and imported keys have no rotation status
+    if keyRotationStatus != nil {
+        setKeyRotationStatus(ko, keyRotationStatus)
+    }
+    recentRotations, err := rm.listRecentRotations(ctx, &resource{ko})
+    if err != nil {
+        return &resource{ko}, err
+    }
+    ko.Status.RecentRotations = recentRotations
@@ -0,0 +1,7 @@
+    input.ReplicaRegion = aws.String(string(rm.awsRegion))
+    // ReplicateKey is only accepted in the Region of the primary key, the
+    // replica key itself is managed in the Region of the resource manager.
+    rm, err = rm.primaryKeyResourceManager(desired)
+    if err != nil {
+        return nil, err
+    }
\ No newline at end of file
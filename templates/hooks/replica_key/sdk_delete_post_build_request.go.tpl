@@ -0,0 +1 @@
+input.PendingWindowInDays = aws.Int32(int32(getDeletePendingWindowInDays(r)))
\ No newline at end of file
@@ -0,0 +1,5 @@
+    if err == nil {
+        ko := r.ko.DeepCopy()
+        setKeyDeletionStatus(ko, resp)
+        return &resource{ko}, nil
+    }
\ No newline at end of file
@@ -0,0 +1,14 @@
+    if isKeyPendingDeletion(ko) && r.IsBeingDeleted() {
+        return &resource{ko}, ackerr.NotFound
+    }
+    setPrimaryKeyID(ko)
+    policy, err := rm.getPolicy(ctx, &resource{ko})
+    if err != nil {
+        return &resource{ko}, err
+    }
+    ko.Spec.Policy = policy
+    tags, err := rm.listTags(ctx, &resource{ko})
+    if err != nil {
+        return &resource{ko}, err
+    }
+    ko.Spec.Tags = fromACKTags(tags, nil)
\ No newline at end of file
@@ -19,6 +19,7 @@ import pytest
 import time
 
 from datetime import datetime, timedelta
+from kubernetes.client.rest import ApiException
 
 from acktest.k8s import resource as k8s
 from acktest.resources import random_suffix_name
@@ -35,6 +36,7 @@ DELETE_WAIT_PERIOD_LENGTH_SECONDS = 10
 KEY_RESOURCE_PLURAL = "keys"
 
 PENDING_WINDOW_IN_DAYS = 8
+SPEC_PENDING_WINDOW_IN_DAYS = 12
 
 @pytest.fixture
 def simple_key():
@@ -244,7 +246,82 @@ class TestKey:
 
         key_policy = kms_client.get_key_policy(KeyId=key_id, PolicyName='default')
         assert 'updated-key-policy' in key_policy['Policy']
-    
+
+    def test_remove_key_policy(self, kms_client, key_with_policy):
+        (ref, cr, _) = key_with_policy
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        k8s.patch_custom_resource(ref, {"spec": {"policy": None, "restoreDefaultPolicy": True}})
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        key_policy = json.loads(kms_client.get_key_policy(KeyId=key_id, PolicyName='default')['Policy'])
+        assert key_policy['Id'] == 'key-default-1'
+
+    def test_update_key_policy_document(self, kms_client, key_with_policy):
+        (ref, cr, _) = key_with_policy
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+        account_id = get_account_id()
+
+        updates = {
+            "spec": {
+                "policy": None,
+                "policyDocument": {
+                    "id": "structured-key-policy",
+                    "statements": [
+                        {
+                            "sid": "Enable IAM User Permissions",
+                            "effect": "Allow",
+                            "principals": {
+                                "aws": [f'arn:aws:iam::{account_id}:root'],
+                            },
+                            "actions": ["kms:*"],
+                        },
+                    ],
+                },
+            }
+        }
+
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        key_policy = json.loads(kms_client.get_key_policy(KeyId=key_id, PolicyName='default')['Policy'])
+        assert key_policy['Id'] == 'structured-key-policy'
+        assert len(key_policy['Statement']) == 1
+        assert key_policy['Statement'][0]['Action'] == 'kms:*'
+
+        cr = k8s.get_resource(ref)
+        assert 'policy' not in cr['spec']
+        assert cr['spec']['policyDocument']['id'] == 'structured-key-policy'
+
+    def test_observe_key_policy_drift(self, kms_client, key_with_policy):
+        (ref, cr, input_policy) = key_with_policy
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        k8s.patch_custom_resource(ref, {"spec": {"policyDriftMode": "observe"}})
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        # Edit the key policy outside of the controller
+        drifted_policy = dict(input_policy, Id='drifted-key-policy')
+        kms_client.put_key_policy(KeyId=key_id, PolicyName='default', Policy=json.dumps(drifted_policy))
+
+        # Any change to the spec makes the controller read the key policy again
+        k8s.patch_custom_resource(ref, {"spec": {"description": "observed key policy drift"}})
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+        assert k8s.wait_on_condition(ref, "KMS.PolicyDrifted", "True", wait_periods=10)
+
+        key_policy = kms_client.get_key_policy(KeyId=key_id, PolicyName='default')
+        assert 'drifted-key-policy' in key_policy['Policy']
+
     def test_create_with_rotation(self, kms_client, key_with_rotation):
         (ref, cr) = key_with_rotation
 
@@ -258,6 +335,99 @@ class TestKey:
         _, deleted = k8s.delete_custom_resource(ref, DELETE_WAIT_PERIODS, DELETE_WAIT_PERIOD_LENGTH_SECONDS)
         assert deleted
 
+    def test_update_rotation(self, kms_client, key_with_rotation):
+        (ref, cr) = key_with_rotation
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        updates = {
+            "spec": {
+                "rotationPeriodInDays": 180,
+                "onDemandRotationGeneration": 1
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        response = kms_client.get_key_rotation_status(KeyId=key_id)
+        assert response['KeyRotationEnabled'] == True
+        assert response['RotationPeriodInDays'] == 180
+
+        cr = k8s.get_resource(ref)
+        assert cr['status']['lastOnDemandRotationGeneration'] == 1
+        assert 'nextRotationDate' in cr['status']
+
+        _, deleted = k8s.delete_custom_resource(ref, DELETE_WAIT_PERIODS, DELETE_WAIT_PERIOD_LENGTH_SECONDS)
+        assert deleted
+
+    def test_disable_enable_key(self, kms_client, simple_key):
+        (ref, cr) = simple_key
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        # enabled is late initialized from DescribeKey
+        cr = k8s.get_resource(ref)
+        assert cr['spec']['enabled'] == True
+
+        updates = {
+            "spec": {
+                "enabled": False
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        key = kms_client.describe_key(KeyId=key_id)
+        assert key['KeyMetadata']['Enabled'] == False
+        assert key['KeyMetadata']['KeyState'] == 'Disabled'
+        assert k8s.assert_condition_state_message(ref, "KMS.KeyUsable", "False", "The KMS key is disabled")
+
+        updates = {
+            "spec": {
+                "enabled": True
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        key = kms_client.describe_key(KeyId=key_id)
+        assert key['KeyMetadata']['Enabled'] == True
+        assert key['KeyMetadata']['KeyState'] == 'Enabled'
+        assert k8s.wait_on_condition(ref, "KMS.KeyUsable", "True", wait_periods=10)
+
+        _, deleted = k8s.delete_custom_resource(ref, DELETE_WAIT_PERIODS, DELETE_WAIT_PERIOD_LENGTH_SECONDS)
+        assert deleted
+
+    def test_update_description(self, kms_client, simple_key):
+        (ref, cr) = simple_key
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        updates = {
+            "spec": {
+                "description": "Key updated by ACK tests"
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        key = kms_client.describe_key(KeyId=key_id)
+        assert key['KeyMetadata']['KeyId'] == key_id
+        assert key['KeyMetadata']['Description'] == "Key updated by ACK tests"
+
+        _, deleted = k8s.delete_custom_resource(ref, DELETE_WAIT_PERIODS, DELETE_WAIT_PERIOD_LENGTH_SECONDS)
+        assert deleted
+
     def test_update_tags(self, kms_client, simple_key):
         (ref, cr) = simple_key
         assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
@@ -383,3 +553,105 @@ class TestKey:
         # Should still exist, and have a deleted timestamp
         key = kms_client.describe_key(KeyId=key_id)
         self._assert_key_deleted(key, PENDING_WINDOW_IN_DAYS)
+
+    def test_delete_key_with_pending_window(self, kms_client, simple_key):
+        (ref, cr) = simple_key
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        updates = {
+            "spec": {
+                "pendingWindowInDays": SPEC_PENDING_WINDOW_IN_DAYS
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        _, deleted = k8s.delete_custom_resource(ref, DELETE_WAIT_PERIODS, DELETE_WAIT_PERIOD_LENGTH_SECONDS)
+        assert deleted
+
+        key = kms_client.describe_key(KeyId=key_id)
+        self._assert_key_deleted(key, SPEC_PENDING_WINDOW_IN_DAYS)
+
+    def test_deletion_protection(self, kms_client, simple_key):
+        (ref, cr) = simple_key
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        assert 'keyID' in cr['status']
+        key_id = cr['status']['keyID']
+
+        updates = {
+            "spec": {
+                "deletionProtectionEnabled": True
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(MODIFY_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(ref, "ACK.ResourceSynced", "True", wait_periods=10)
+
+        # The CR stays around and the key is not scheduled for deletion
+        _, deleted = k8s.delete_custom_resource(ref, DELETE_WAIT_PERIODS, DELETE_WAIT_PERIOD_LENGTH_SECONDS)
+        assert not deleted
+        assert k8s.wait_on_condition(ref, "ACK.Terminal", "True", wait_periods=10)
+
+        key = kms_client.describe_key(KeyId=key_id)
+        self._assert_key_alive(key)
+
+        updates = {
+            "spec": {
+                "deletionProtectionEnabled": False
+            }
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        assert not k8s.get_resource_exists(ref)
+
+        key = kms_client.describe_key(KeyId=key_id)
+        self._assert_key_deleted(key)
+
+    @pytest.mark.parametrize("spec, message", [
+        (
+            {"keySpec": "HMAC_256", "keyUsage": "ENCRYPT_DECRYPT"},
+            "HMAC keySpecs require keyUsage GENERATE_VERIFY_MAC",
+        ),
+        (
+            {"origin": "AWS_CLOUDHSM"},
+            "origin AWS_CLOUDHSM and EXTERNAL_KEY_STORE require customKeyStoreID or customKeyStoreRef",
+        ),
+        (
+            {"origin": "AWS_CLOUDHSM", "customKeyStoreID": "cks-1234567890abcdef0", "multiRegion": True},
+            "multiRegion keys cannot be created in a custom key store",
+        ),
+        (
+            {"xksKeyID": "xks-key-1"},
+            "xksKeyID can only be set along with customKeyStoreID or customKeyStoreRef",
+        ),
+        (
+            {"keySpec": "RSA_2048", "keyUsage": "KEY_AGREEMENT"},
+            "keyUsage KEY_AGREEMENT requires a keySpec of ECC_NIST_P256, ECC_NIST_P384, ECC_NIST_P521 or SM2",
+        ),
+    ])
+    def test_reject_invalid_key_spec_combinations(self, spec, message):
+        key_name = random_suffix_name("invalid-key", 32)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements["KEY_NAME"] = key_name
+
+        resource_data = load_kms_resource(
+            "key_simple",
+            additional_replacements=replacements,
+        )
+        resource_data["spec"].update(spec)
+
+        ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, KEY_RESOURCE_PLURAL,
+            key_name, namespace="default",
+        )
+        with pytest.raises(ApiException) as e:
+            k8s.create_custom_resource(ref, resource_data)
+        assert e.value.status == 422
+        assert message in e.value.body
+        assert not k8s.get_resource_exists(ref)